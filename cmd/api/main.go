@@ -13,8 +13,10 @@ import (
 	"github.com/senoagung27/warehousex/internal/config"
 	"github.com/senoagung27/warehousex/internal/controller"
 	"github.com/senoagung27/warehousex/internal/infrastructure"
+	"github.com/senoagung27/warehousex/internal/middleware"
 	"github.com/senoagung27/warehousex/internal/repository"
 	"github.com/senoagung27/warehousex/internal/router"
+	"github.com/senoagung27/warehousex/internal/scheduler"
 	"github.com/senoagung27/warehousex/internal/service"
 	"go.uber.org/zap"
 )
@@ -47,33 +49,101 @@ func main() {
 	}
 	logger.Info("Redis connected")
 
+	// ========== Locks ==========
+	locker, err := infrastructure.NewLocker(cfg.Lock, redisClient, db, logger)
+	if err != nil {
+		logger.Fatal("Failed to initialize lock backend", zap.Error(err))
+	}
+	logger.Info("Lock backend ready", zap.String("backend", cfg.Lock.Backend))
+
+	// ========== Signing Keys ==========
+	keys, err := infrastructure.NewKeySet(&cfg.JWT)
+	if err != nil {
+		logger.Fatal("Failed to load JWT signing keys", zap.Error(err))
+	}
+	logger.Info("JWT signing keys loaded",
+		zap.String("kid", keys.SigningKeyID()),
+		zap.String("alg", keys.Algorithm()),
+	)
+
+	// ========== Blob Storage ==========
+	blobStore, err := infrastructure.NewBlobStore(&cfg.Storage)
+	if err != nil {
+		logger.Fatal("Failed to initialize blob storage", zap.Error(err))
+	}
+	logger.Info("Blob storage ready", zap.String("driver", cfg.Storage.Driver))
+
 	// ========== Repositories ==========
 	userRepo := repository.NewUserRepository(db)
 	inventoryRepo := repository.NewInventoryRepository(db)
+	stockRepo := repository.NewStockRepository(db)
+	warehouseRepo := repository.NewWarehouseRepository(db)
+	locationRepo := repository.NewLocationRepository(db)
+	lotRepo := repository.NewLotRepository(db)
+	serialRepo := repository.NewSerialRepository(db)
+	movementRepo := repository.NewMovementRepository(db)
 	requestRepo := repository.NewRequestRepository(db)
+	policyRepo := repository.NewApprovalPolicyRepository(db)
+	delegationRepo := repository.NewDelegationRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	invitationRepo := repository.NewInvitationRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
 	auditLogRepo := repository.NewAuditLogRepository(db)
 
+	// ========== Notifications ==========
+	notifier := infrastructure.NewLogNotifier(logger)
+
 	// ========== Services ==========
-	authService := service.NewAuthService(userRepo, cfg.JWT, logger)
-	inventoryService := service.NewInventoryService(inventoryRepo, auditLogRepo, logger)
-	requestService := service.NewRequestService(requestRepo, inventoryRepo, auditLogRepo, redisClient, db, logger)
+	authService := service.NewAuthService(userRepo, invitationRepo, refreshTokenRepo, auditLogRepo, redisClient, keys, cfg.JWT, cfg.Auth, cfg.Login, db, logger)
+	inventoryService := service.NewInventoryService(inventoryRepo, stockRepo, lotRepo, serialRepo, movementRepo, warehouseRepo, auditLogRepo, logger)
+	warehouseService := service.NewWarehouseService(warehouseRepo, locationRepo, auditLogRepo, logger)
+	requestService := service.NewRequestService(requestRepo, inventoryRepo, stockRepo, warehouseRepo, locationRepo, lotRepo, serialRepo, movementRepo, policyRepo, delegationRepo, userRepo, auditLogRepo, locker, notifier, db, logger)
+	policyService := service.NewApprovalPolicyService(policyRepo, auditLogRepo, logger)
+	delegationService := service.NewDelegationService(delegationRepo, userRepo, auditLogRepo, logger)
+	attachmentService := service.NewAttachmentService(attachmentRepo, requestRepo, auditLogRepo, blobStore, cfg.Storage, db, logger)
 	auditService := service.NewAuditService(auditLogRepo, logger)
+	invitationService := service.NewInvitationService(invitationRepo, userRepo, auditLogRepo, cfg.Auth, logger)
+	userService := service.NewUserService(userRepo, auditLogRepo, authService, cfg.Auth, logger)
 
 	// ========== Controllers ==========
 	authController := controller.NewAuthController(authService)
 	inventoryController := controller.NewInventoryController(inventoryService)
+	warehouseController := controller.NewWarehouseController(warehouseService)
 	requestController := controller.NewRequestController(requestService)
+	policyController := controller.NewApprovalPolicyController(policyService)
+	delegationController := controller.NewDelegationController(delegationService)
+	attachmentController := controller.NewAttachmentController(attachmentService, cfg.Storage.MaxFileSize)
 	auditController := controller.NewAuditController(auditService)
+	invitationController := controller.NewInvitationController(invitationService)
+	userController := controller.NewUserController(userService)
 
 	// ========== Router ==========
-	r := router.NewRouter(
+	r, err := router.NewRouter(
 		authController,
 		inventoryController,
+		warehouseController,
 		requestController,
+		policyController,
+		delegationController,
+		attachmentController,
 		auditController,
-		cfg.JWT.Secret,
+		invitationController,
+		userController,
+		middleware.JWTAuth(keys, userRepo, redisClient),
 		cfg.Server.GinMode,
+		cfg.Server.TrustedProxies,
+		middleware.Idempotency(redisClient, locker, cfg.Idempotency.TTL),
 	)
+	if err != nil {
+		logger.Fatal("Failed to initialize router", zap.Error(err))
+	}
+
+	// ========== Scheduler ==========
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	if cfg.Scheduler.Enabled {
+		go scheduler.NewScheduler(cfg.Scheduler, requestService, locker, logger).Run(schedulerCtx)
+	}
 
 	// ========== Server ==========
 	srv := &http.Server{
@@ -100,6 +170,7 @@ func main() {
 	<-quit
 
 	logger.Info("Shutting down server...")
+	stopScheduler()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -0,0 +1,84 @@
+// Command bootstrap creates the first admin account. It refuses to run once an
+// admin exists; further users are invited through the API.
+//
+//	go run ./cmd/bootstrap -email admin@example.com -name "Admin"
+//
+// The password is read from BOOTSTRAP_ADMIN_PASSWORD, or from stdin when unset.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/senoagung27/warehousex/internal/config"
+	"github.com/senoagung27/warehousex/internal/dto"
+	"github.com/senoagung27/warehousex/internal/infrastructure"
+	"github.com/senoagung27/warehousex/internal/repository"
+	"github.com/senoagung27/warehousex/internal/service"
+	"go.uber.org/zap"
+)
+
+func main() {
+	email := flag.String("email", "", "admin email")
+	name := flag.String("name", "Administrator", "admin name")
+	flag.Parse()
+
+	if *email == "" {
+		log.Fatal("-email is required")
+	}
+
+	password, ok := os.LookupEnv("BOOTSTRAP_ADMIN_PASSWORD")
+	if !ok {
+		fmt.Fprint(os.Stderr, "Password: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			log.Fatalf("Failed to read password: %v", err)
+		}
+		password = strings.TrimRight(line, "\r\n")
+	}
+	if len(password) < 6 {
+		log.Fatal("password must be at least 6 characters")
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Sync()
+
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatal("Failed to load config", zap.Error(err))
+	}
+
+	db, err := infrastructure.NewDatabase(&cfg.Database, logger)
+	if err != nil {
+		logger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+	sqlDB, _ := db.DB()
+	if sqlDB != nil {
+		defer sqlDB.Close()
+	}
+
+	userRepo := repository.NewUserRepository(db)
+	invitationRepo := repository.NewInvitationRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	auditLogRepo := repository.NewAuditLogRepository(db)
+	// Creating a user issues no tokens, so neither Redis nor signing keys are needed
+	authService := service.NewAuthService(userRepo, invitationRepo, refreshTokenRepo, auditLogRepo, nil, nil, cfg.JWT, cfg.Auth, cfg.Login, db, logger)
+
+	user, err := authService.BootstrapAdmin(dto.BootstrapAdminInput{
+		Name:     *name,
+		Email:    *email,
+		Password: password,
+	})
+	if err != nil {
+		logger.Fatal("Failed to create admin", zap.Error(err))
+	}
+
+	fmt.Printf("Admin %s created (id %s)\n", user.Email, user.ID)
+}
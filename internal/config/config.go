@@ -4,20 +4,31 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
+	Server      ServerConfig
+	Database    DatabaseConfig
+	Redis       RedisConfig
+	Lock        LockConfig
+	JWT         JWTConfig
+	Auth        AuthConfig
+	Login       LoginThrottleConfig
+	Scheduler   SchedulerConfig
+	Storage     StorageConfig
+	Idempotency IdempotencyConfig
 }
 
 type ServerConfig struct {
 	Port    string
 	GinMode string
+	// TrustedProxies are the proxy IPs/CIDRs whose X-Forwarded-For is believed when
+	// resolving the client IP; none means only the socket peer is used
+	TrustedProxies []string
 }
 
 type DatabaseConfig struct {
@@ -36,9 +47,99 @@ type RedisConfig struct {
 	DB       int
 }
 
+// LockConfig selects the lock backend ("redis", "postgres" or "memory"). A lock is
+// retried with backoff between RetryMin and RetryMax for up to Wait, and its lease
+// of TTL is renewed while it is held.
+type LockConfig struct {
+	Backend  string
+	TTL      time.Duration
+	Wait     time.Duration
+	RetryMin time.Duration
+	RetryMax time.Duration
+}
+
+// IdempotencyConfig is how long responses to requests with an Idempotency-Key are
+// kept for replay
+type IdempotencyConfig struct {
+	TTL time.Duration
+}
+
+// DefaultJWTSecret is the placeholder secret, refused outside debug mode
+const DefaultJWTSecret = "default-secret"
+
+// MinJWTSecretLength is the shortest HS256 secret accepted outside debug mode, the
+// size of the hash
+const MinJWTSecretLength = 32
+
+// JWTConfig signs short-lived access tokens; sessions are kept alive by rotating
+// refresh tokens that last RefreshTTL. Tokens are signed with the RSA or Ed25519
+// keys in KeyFiles (PEM, kid from the file name; SigningKeyID or the first private
+// key signs) or, without key files, with the HMAC Secret.
 type JWTConfig struct {
-	Secret          string
-	ExpirationHours int
+	Secret       string
+	KeyFiles     []string
+	SigningKeyID string
+	AccessTTL    time.Duration
+	RefreshTTL   time.Duration
+}
+
+// Registration modes for the public register endpoint
+const (
+	RegistrationDisabled = "disabled"
+	RegistrationPending  = "pending"
+)
+
+// AuthConfig controls onboarding: whether public registration is closed or creates
+// staff accounts pending admin activation, and how long invitations and
+// admin-issued password reset tokens stay valid
+type AuthConfig struct {
+	Registration string
+	InviteTTL    time.Duration
+	ResetTTL     time.Duration
+}
+
+// LoginThrottleConfig slows down password guessing. Failed logins are counted per
+// account and per client IP, forgotten Window after the last one. Past the free
+// attempts, each failure blocks further logins for a backoff doubling from
+// BackoffBase up to BackoffMax, and LockoutThreshold failures on an account lock
+// it for LockoutDuration or until an admin unlocks it.
+type LoginThrottleConfig struct {
+	Window              time.Duration
+	AccountFreeAttempts int
+	IPFreeAttempts      int
+	BackoffBase         time.Duration
+	BackoffMax          time.Duration
+	LockoutThreshold    int
+	LockoutDuration     time.Duration
+}
+
+// SchedulerConfig controls the background jobs run by the API. SLAs are keyed by
+// request type.
+type SchedulerConfig struct {
+	Enabled  bool
+	Interval time.Duration
+	SLAs     map[string]SLAConfig
+}
+
+// SLAConfig is how long a request may stay pending before it is escalated to the
+// next approver tier and before it expires; zero disables either
+type SLAConfig struct {
+	EscalateAfter time.Duration
+	ExpireAfter   time.Duration
+}
+
+// StorageConfig selects the blob store holding request attachments ("local" or
+// "s3") and the limits applied to uploads
+type StorageConfig struct {
+	Driver       string
+	LocalPath    string
+	S3Endpoint   string
+	S3Region     string
+	S3Bucket     string
+	S3AccessKey  string
+	S3SecretKey  string
+	MaxFileSize  int64
+	AllowedTypes []string
 }
 
 func (d *DatabaseConfig) DSN() string {
@@ -56,12 +157,88 @@ func Load() (*Config, error) {
 	_ = godotenv.Load()
 
 	redisDB, _ := strconv.Atoi(getEnv("REDIS_DB", "0"))
-	jwtExpHours, _ := strconv.Atoi(getEnv("JWT_EXPIRATION_HOURS", "24"))
+	lockTTL, err := getEnvPositiveInt("LOCK_TTL_SECONDS", "10")
+	if err != nil {
+		return nil, err
+	}
+	lockWait, err := getEnvPositiveInt("LOCK_WAIT_MS", "3000")
+	if err != nil {
+		return nil, err
+	}
+	lockRetryMin, err := getEnvPositiveInt("LOCK_RETRY_MIN_MS", "20")
+	if err != nil {
+		return nil, err
+	}
+	lockRetryMax, err := getEnvPositiveInt("LOCK_RETRY_MAX_MS", "500")
+	if err != nil {
+		return nil, err
+	}
+	if lockRetryMin > lockRetryMax {
+		return nil, fmt.Errorf("LOCK_RETRY_MIN_MS (%d) must not exceed LOCK_RETRY_MAX_MS (%d)", lockRetryMin, lockRetryMax)
+	}
+	jwtAccessTTL, err := getEnvPositiveInt("JWT_ACCESS_TTL_MINUTES", "15")
+	if err != nil {
+		return nil, err
+	}
+	jwtRefreshTTL, err := getEnvPositiveInt("JWT_REFRESH_TTL_HOURS", "720")
+	if err != nil {
+		return nil, err
+	}
+	inviteTTL, err := getEnvPositiveInt("INVITE_TTL_HOURS", "72")
+	if err != nil {
+		return nil, err
+	}
+	resetTTL, err := getEnvPositiveInt("PASSWORD_RESET_TTL_HOURS", "24")
+	if err != nil {
+		return nil, err
+	}
+	loginWindow, err := getEnvPositiveInt("LOGIN_FAILURE_WINDOW_MINUTES", "15")
+	if err != nil {
+		return nil, err
+	}
+	loginAccountFree, err := getEnvNonNegativeInt("LOGIN_ACCOUNT_FREE_ATTEMPTS", "3")
+	if err != nil {
+		return nil, err
+	}
+	loginIPFree, err := getEnvNonNegativeInt("LOGIN_IP_FREE_ATTEMPTS", "20")
+	if err != nil {
+		return nil, err
+	}
+	loginBackoffBase, err := getEnvPositiveInt("LOGIN_BACKOFF_BASE_SECONDS", "1")
+	if err != nil {
+		return nil, err
+	}
+	loginBackoffMax, err := getEnvPositiveInt("LOGIN_BACKOFF_MAX_SECONDS", "300")
+	if err != nil {
+		return nil, err
+	}
+	if loginBackoffBase > loginBackoffMax {
+		return nil, fmt.Errorf("LOGIN_BACKOFF_BASE_SECONDS (%d) must not exceed LOGIN_BACKOFF_MAX_SECONDS (%d)", loginBackoffBase, loginBackoffMax)
+	}
+	// Zero disables lockout
+	loginLockoutThreshold, err := getEnvNonNegativeInt("LOGIN_LOCKOUT_THRESHOLD", "10")
+	if err != nil {
+		return nil, err
+	}
+	loginLockout, err := getEnvPositiveInt("LOGIN_LOCKOUT_MINUTES", "15")
+	if err != nil {
+		return nil, err
+	}
+	schedulerInterval, err := getEnvPositiveInt("SCHEDULER_INTERVAL_SECONDS", "60")
+	if err != nil {
+		return nil, err
+	}
+	idempotencyTTL, err := getEnvPositiveInt("IDEMPOTENCY_TTL_HOURS", "24")
+	if err != nil {
+		return nil, err
+	}
+	maxFileSizeMB, _ := strconv.Atoi(getEnv("STORAGE_MAX_FILE_SIZE_MB", "10"))
 
 	cfg := &Config{
 		Server: ServerConfig{
-			Port:    getEnv("SERVER_PORT", "8080"),
-			GinMode: getEnv("GIN_MODE", "debug"),
+			Port:           getEnv("SERVER_PORT", "8080"),
+			GinMode:        getEnv("GIN_MODE", "debug"),
+			TrustedProxies: splitList(getEnv("TRUSTED_PROXIES", "")),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -77,15 +254,119 @@ func Load() (*Config, error) {
 			Password: getEnv("REDIS_PASSWORD", ""),
 			DB:       redisDB,
 		},
+		Lock: LockConfig{
+			Backend:  getEnv("LOCK_BACKEND", "redis"),
+			TTL:      time.Duration(lockTTL) * time.Second,
+			Wait:     time.Duration(lockWait) * time.Millisecond,
+			RetryMin: time.Duration(lockRetryMin) * time.Millisecond,
+			RetryMax: time.Duration(lockRetryMax) * time.Millisecond,
+		},
 		JWT: JWTConfig{
-			Secret:          getEnv("JWT_SECRET", "default-secret"),
-			ExpirationHours: jwtExpHours,
+			Secret:       getEnv("JWT_SECRET", DefaultJWTSecret),
+			KeyFiles:     splitList(getEnv("JWT_KEY_FILES", "")),
+			SigningKeyID: getEnv("JWT_SIGNING_KEY_ID", ""),
+			AccessTTL:    time.Duration(jwtAccessTTL) * time.Minute,
+			RefreshTTL:   time.Duration(jwtRefreshTTL) * time.Hour,
 		},
+		Auth: AuthConfig{
+			Registration: getEnv("REGISTRATION_MODE", RegistrationDisabled),
+			InviteTTL:    time.Duration(inviteTTL) * time.Hour,
+			ResetTTL:     time.Duration(resetTTL) * time.Hour,
+		},
+		Login: LoginThrottleConfig{
+			Window:              time.Duration(loginWindow) * time.Minute,
+			AccountFreeAttempts: loginAccountFree,
+			IPFreeAttempts:      loginIPFree,
+			BackoffBase:         time.Duration(loginBackoffBase) * time.Second,
+			BackoffMax:          time.Duration(loginBackoffMax) * time.Second,
+			LockoutThreshold:    loginLockoutThreshold,
+			LockoutDuration:     time.Duration(loginLockout) * time.Minute,
+		},
+		Scheduler: SchedulerConfig{
+			Enabled:  getEnv("SCHEDULER_ENABLED", "true") == "true",
+			Interval: time.Duration(schedulerInterval) * time.Second,
+			SLAs: map[string]SLAConfig{
+				"INBOUND":  loadSLA("INBOUND"),
+				"OUTBOUND": loadSLA("OUTBOUND"),
+				"TRANSFER": loadSLA("TRANSFER"),
+			},
+		},
+		Storage: StorageConfig{
+			Driver:       getEnv("STORAGE_DRIVER", "local"),
+			LocalPath:    getEnv("STORAGE_LOCAL_PATH", "./data/attachments"),
+			S3Endpoint:   getEnv("S3_ENDPOINT", "http://localhost:9000"),
+			S3Region:     getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:     getEnv("S3_BUCKET", "warehousex"),
+			S3AccessKey:  getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey:  getEnv("S3_SECRET_KEY", ""),
+			MaxFileSize:  int64(maxFileSizeMB) << 20,
+			AllowedTypes: strings.Split(getEnv("STORAGE_ALLOWED_TYPES", "application/pdf,image/jpeg,image/png,image/webp"), ","),
+		},
+		Idempotency: IdempotencyConfig{
+			TTL: time.Duration(idempotencyTTL) * time.Hour,
+		},
+	}
+
+	if len(cfg.JWT.KeyFiles) == 0 {
+		switch {
+		case cfg.JWT.Secret == "":
+			return nil, fmt.Errorf("JWT_SECRET is empty; set it or JWT_KEY_FILES")
+		case cfg.Server.GinMode == "debug":
+		case cfg.JWT.Secret == DefaultJWTSecret:
+			return nil, fmt.Errorf("JWT_SECRET is the default %q; set it or JWT_KEY_FILES outside debug mode", DefaultJWTSecret)
+		case len(cfg.JWT.Secret) < MinJWTSecretLength:
+			return nil, fmt.Errorf("JWT_SECRET must be at least %d bytes outside debug mode", MinJWTSecretLength)
+		}
 	}
 
 	return cfg, nil
 }
 
+// loadSLA reads SLA_<TYPE>_ESCALATE_HOURS and SLA_<TYPE>_EXPIRE_DAYS
+func loadSLA(reqType string) SLAConfig {
+	escalateHours, _ := strconv.Atoi(getEnv("SLA_"+reqType+"_ESCALATE_HOURS", "24"))
+	expireDays, _ := strconv.Atoi(getEnv("SLA_"+reqType+"_EXPIRE_DAYS", "7"))
+	return SLAConfig{
+		EscalateAfter: time.Duration(escalateHours) * time.Hour,
+		ExpireAfter:   time.Duration(expireDays) * 24 * time.Hour,
+	}
+}
+
+// splitList splits a comma-separated value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getEnvPositiveInt reads an integer setting that must be above zero
+func getEnvPositiveInt(key, defaultValue string) (int, error) {
+	value, err := strconv.Atoi(getEnv(key, defaultValue))
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer: %w", key, err)
+	}
+	if value <= 0 {
+		return 0, fmt.Errorf("%s must be positive, got %d", key, value)
+	}
+	return value, nil
+}
+
+// getEnvNonNegativeInt reads an integer setting for which zero is meaningful
+func getEnvNonNegativeInt(key, defaultValue string) (int, error) {
+	value, err := strconv.Atoi(getEnv(key, defaultValue))
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer: %w", key, err)
+	}
+	if value < 0 {
+		return 0, fmt.Errorf("%s must not be negative, got %d", key, value)
+	}
+	return value, nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/dto"
+	"github.com/senoagung27/warehousex/internal/middleware"
+	"github.com/senoagung27/warehousex/internal/service"
+)
+
+type ApprovalPolicyController struct {
+	policyService service.ApprovalPolicyServiceInterface
+}
+
+func NewApprovalPolicyController(policyService service.ApprovalPolicyServiceInterface) *ApprovalPolicyController {
+	return &ApprovalPolicyController{policyService: policyService}
+}
+
+// Create godoc
+// @Summary Create an approval policy
+// @Tags Approval Policies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body dto.CreateApprovalPolicyInput true "Create Approval Policy Input"
+// @Success 201 {object} model.ApprovalPolicy
+// @Router /api/v1/approval-policies [post]
+func (ctrl *ApprovalPolicyController) Create(c *gin.Context) {
+	var input dto.CreateApprovalPolicyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	policy, err := ctrl.policyService.Create(input, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "approval policy created",
+		"data":    policy,
+	})
+}
+
+// GetAll godoc
+// @Summary List approval policies
+// @Tags Approval Policies
+// @Security BearerAuth
+// @Produce json
+// @Param include_inactive query bool false "Include deactivated policies"
+// @Success 200 {array} model.ApprovalPolicy
+// @Router /api/v1/approval-policies [get]
+func (ctrl *ApprovalPolicyController) GetAll(c *gin.Context) {
+	policies, err := ctrl.policyService.GetAll(c.Query("include_inactive") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": policies})
+}
+
+// Deactivate godoc
+// @Summary Deactivate an approval policy
+// @Tags Approval Policies
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Approval Policy ID"
+// @Success 200 {object} model.ApprovalPolicy
+// @Router /api/v1/approval-policies/{id} [delete]
+func (ctrl *ApprovalPolicyController) Deactivate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid approval policy ID"})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	policy, err := ctrl.policyService.Deactivate(id, userID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "approval policy deactivated",
+		"data":    policy,
+	})
+}
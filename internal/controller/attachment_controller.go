@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/middleware"
+	"github.com/senoagung27/warehousex/internal/service"
+)
+
+// multipartOverhead is the room left in an upload body for the multipart framing
+const multipartOverhead = 1 << 20
+
+type AttachmentController struct {
+	attachmentService service.AttachmentServiceInterface
+	maxFileSize       int64
+}
+
+func NewAttachmentController(attachmentService service.AttachmentServiceInterface, maxFileSize int64) *AttachmentController {
+	return &AttachmentController{attachmentService: attachmentService, maxFileSize: maxFileSize}
+}
+
+// Upload godoc
+// @Summary Attach a file to a request (creator, supervisor or admin)
+// @Tags Attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Request ID"
+// @Param file formData file true "PDF or image"
+// @Success 201 {object} model.RequestAttachment
+// @Router /api/v1/requests/{id}/attachments [post]
+func (ctrl *AttachmentController) Upload(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request ID"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, ctrl.maxFileSize+multipartOverhead)
+	header, err := c.FormFile("file")
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if strings.Contains(err.Error(), "request body too large") {
+			statusCode = http.StatusRequestEntityTooLarge
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+
+	attachment, err := ctrl.attachmentService.Upload(c.Request.Context(), id, userID, userRole, header.Filename, header.Size, file)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		switch {
+		case strings.Contains(err.Error(), "insufficient permissions"):
+			statusCode = http.StatusForbidden
+		case strings.Contains(err.Error(), "not found"):
+			statusCode = http.StatusNotFound
+		case strings.Contains(err.Error(), "file too large"):
+			statusCode = http.StatusRequestEntityTooLarge
+		case strings.Contains(err.Error(), "unsupported file type"):
+			statusCode = http.StatusUnsupportedMediaType
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "attachment uploaded",
+		"data":    attachment,
+	})
+}
+
+// GetAll godoc
+// @Summary List a request's attachments (creator, supervisor, admin or auditor)
+// @Tags Attachments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Request ID"
+// @Success 200 {array} model.RequestAttachment
+// @Router /api/v1/requests/{id}/attachments [get]
+func (ctrl *AttachmentController) GetAll(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request ID"})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+
+	attachments, err := ctrl.attachmentService.GetAll(id, userID, userRole)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case strings.Contains(err.Error(), "insufficient permissions"):
+			statusCode = http.StatusForbidden
+		case strings.Contains(err.Error(), "not found"):
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": attachments})
+}
+
+// Download godoc
+// @Summary Download an attachment (creator, supervisor, admin or auditor)
+// @Tags Attachments
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param id path string true "Request ID"
+// @Param attachment_id path string true "Attachment ID"
+// @Success 200 {file} file
+// @Router /api/v1/requests/{id}/attachments/{attachment_id} [get]
+func (ctrl *AttachmentController) Download(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request ID"})
+		return
+	}
+	attachmentID, err := uuid.Parse(c.Param("attachment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attachment ID"})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+
+	attachment, content, err := ctrl.attachmentService.Download(c.Request.Context(), id, attachmentID, userID, userRole)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case strings.Contains(err.Error(), "insufficient permissions"):
+			statusCode = http.StatusForbidden
+		case strings.Contains(err.Error(), "not found"):
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+		"X-Checksum-SHA256":      attachment.Checksum,
+		"X-Content-Type-Options": "nosniff",
+		"Cache-Control":          "private, no-store",
+	})
+}
@@ -1,10 +1,15 @@
 package controller
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/senoagung27/warehousex/internal/dto"
+	"github.com/senoagung27/warehousex/internal/middleware"
 	"github.com/senoagung27/warehousex/internal/service"
 )
 
@@ -17,12 +22,12 @@ func NewAuthController(authService service.AuthServiceInterface) *AuthController
 }
 
 // Register godoc
-// @Summary Register a new user
+// @Summary Register a staff account pending admin activation (when enabled)
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param input body dto.RegisterInput true "Register Input"
-// @Success 201 {object} dto.AuthResponse
+// @Success 201 {object} model.User
 // @Router /api/v1/auth/register [post]
 func (ctrl *AuthController) Register(c *gin.Context) {
 	var input dto.RegisterInput
@@ -31,7 +36,38 @@ func (ctrl *AuthController) Register(c *gin.Context) {
 		return
 	}
 
-	response, err := ctrl.authService.Register(input)
+	user, err := ctrl.authService.Register(input)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if strings.Contains(err.Error(), "registration is disabled") {
+			statusCode = http.StatusForbidden
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "registration received; an admin must activate the account",
+		"data":    user,
+	})
+}
+
+// AcceptInvitation godoc
+// @Summary Create an account from an invitation
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body dto.AcceptInvitationInput true "Accept Invitation Input"
+// @Success 201 {object} dto.AuthResponse
+// @Router /api/v1/auth/accept-invitation [post]
+func (ctrl *AuthController) AcceptInvitation(c *gin.Context) {
+	var input dto.AcceptInvitationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := ctrl.authService.AcceptInvitation(input)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -43,6 +79,33 @@ func (ctrl *AuthController) Register(c *gin.Context) {
 	})
 }
 
+// ResetPassword godoc
+// @Summary Set a new password with a reset token issued by an admin
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body dto.ResetPasswordInput true "Reset Password Input"
+// @Success 200 {object} model.User
+// @Router /api/v1/auth/reset-password [post]
+func (ctrl *AuthController) ResetPassword(c *gin.Context) {
+	var input dto.ResetPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := ctrl.authService.ResetPassword(c.Request.Context(), input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "password reset; log in with the new password",
+		"data":    user,
+	})
+}
+
 // Login godoc
 // @Summary Login user
 // @Tags Auth
@@ -58,9 +121,23 @@ func (ctrl *AuthController) Login(c *gin.Context) {
 		return
 	}
 
-	response, err := ctrl.authService.Login(input)
+	response, err := ctrl.authService.Login(c.Request.Context(), input, c.ClientIP())
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		var throttled *service.LoginThrottledError
+		if errors.As(err, &throttled) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+
+		statusCode := http.StatusUnauthorized
+		switch {
+		case strings.Contains(err.Error(), "failed to"):
+			statusCode = http.StatusInternalServerError
+		case strings.Contains(err.Error(), "account"), strings.Contains(err.Error(), "reset required"):
+			statusCode = http.StatusForbidden
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -69,3 +146,81 @@ func (ctrl *AuthController) Login(c *gin.Context) {
 		"data":    response,
 	})
 }
+
+// Refresh godoc
+// @Summary Exchange a refresh token for a new access and refresh token
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body dto.RefreshInput true "Refresh Input"
+// @Success 200 {object} dto.AuthResponse
+// @Router /api/v1/auth/refresh [post]
+func (ctrl *AuthController) Refresh(c *gin.Context) {
+	var input dto.RefreshInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := ctrl.authService.Refresh(c.Request.Context(), input)
+	if err != nil {
+		statusCode := http.StatusUnauthorized
+		switch {
+		case strings.Contains(err.Error(), "failed to"):
+			statusCode = http.StatusInternalServerError
+		case strings.Contains(err.Error(), "account"), strings.Contains(err.Error(), "reset required"):
+			statusCode = http.StatusForbidden
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "token refreshed",
+		"data":    response,
+	})
+}
+
+// Logout godoc
+// @Summary Revoke the access token and end its session
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body dto.LogoutInput false "Logout Input"
+// @Success 200 {object} map[string]string
+// @Router /api/v1/auth/logout [post]
+func (ctrl *AuthController) Logout(c *gin.Context) {
+	var input dto.LogoutInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	err := ctrl.authService.Logout(
+		c.Request.Context(),
+		middleware.GetUserID(c),
+		middleware.GetTokenID(c),
+		middleware.GetTokenExpiry(c),
+		input,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "logged out"})
+}
+
+// JWKS godoc
+// @Summary Public keys for verifying access tokens
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} infrastructure.JWKS
+// @Router /.well-known/jwks.json [get]
+func (ctrl *AuthController) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, ctrl.authService.JWKS())
+}
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/dto"
+	"github.com/senoagung27/warehousex/internal/middleware"
+	"github.com/senoagung27/warehousex/internal/service"
+)
+
+type DelegationController struct {
+	delegationService service.DelegationServiceInterface
+}
+
+func NewDelegationController(delegationService service.DelegationServiceInterface) *DelegationController {
+	return &DelegationController{delegationService: delegationService}
+}
+
+// Create godoc
+// @Summary Delegate approval authority for a date range
+// @Tags Delegations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body dto.CreateDelegationInput true "Create Delegation Input"
+// @Success 201 {object} model.ApprovalDelegation
+// @Router /api/v1/delegations [post]
+func (ctrl *DelegationController) Create(c *gin.Context) {
+	var input dto.CreateDelegationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+
+	delegation, err := ctrl.delegationService.Create(input, userID, userRole)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		switch {
+		case strings.Contains(err.Error(), "insufficient permissions"):
+			statusCode = http.StatusForbidden
+		case strings.Contains(err.Error(), "not found"):
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "delegation created",
+		"data":    delegation,
+	})
+}
+
+// GetAll godoc
+// @Summary List delegations given or received (admins see all)
+// @Tags Delegations
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} model.ApprovalDelegation
+// @Router /api/v1/delegations [get]
+func (ctrl *DelegationController) GetAll(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+
+	delegations, err := ctrl.delegationService.GetAll(userID, userRole)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": delegations})
+}
+
+// Revoke godoc
+// @Summary Revoke a delegation
+// @Tags Delegations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Delegation ID"
+// @Success 200 {object} model.ApprovalDelegation
+// @Router /api/v1/delegations/{id} [delete]
+func (ctrl *DelegationController) Revoke(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid delegation ID"})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+
+	delegation, err := ctrl.delegationService.Revoke(id, userID, userRole)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case strings.Contains(err.Error(), "insufficient permissions"):
+			statusCode = http.StatusForbidden
+		case strings.Contains(err.Error(), "not found"):
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "delegation revoked",
+		"data":    delegation,
+	})
+}
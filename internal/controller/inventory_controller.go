@@ -1,8 +1,11 @@
 package controller
 
 import (
+	"encoding/csv"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -38,7 +41,11 @@ func (ctrl *InventoryController) Create(c *gin.Context) {
 	userID := middleware.GetUserID(c)
 	item, err := ctrl.inventoryService.Create(input, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "warehouse") || strings.Contains(err.Error(), "serialized") {
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -55,13 +62,20 @@ func (ctrl *InventoryController) Create(c *gin.Context) {
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(20)
+// @Param as_of query string false "Balances as of RFC3339 time or end of YYYY-MM-DD"
 // @Success 200 {array} model.Inventory
 // @Router /api/v1/inventory [get]
 func (ctrl *InventoryController) GetAll(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 
-	items, total, err := ctrl.inventoryService.GetAll(page, limit)
+	asOf, err := parseTimeQuery(c.Query("as_of"), true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid as_of: use RFC3339 or YYYY-MM-DD"})
+		return
+	}
+
+	items, total, err := ctrl.inventoryService.GetAll(page, limit, asOf)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -76,11 +90,12 @@ func (ctrl *InventoryController) GetAll(c *gin.Context) {
 }
 
 // GetByID godoc
-// @Summary Get inventory item by ID
+// @Summary Get inventory item by ID with per-warehouse stock breakdown
 // @Tags Inventory
 // @Security BearerAuth
 // @Produce json
 // @Param id path string true "Item ID"
+// @Param as_of query string false "Balances as of RFC3339 time or end of YYYY-MM-DD"
 // @Success 200 {object} model.Inventory
 // @Router /api/v1/inventory/{id} [get]
 func (ctrl *InventoryController) GetByID(c *gin.Context) {
@@ -90,8 +105,18 @@ func (ctrl *InventoryController) GetByID(c *gin.Context) {
 		return
 	}
 
-	item, err := ctrl.inventoryService.GetByID(id)
+	asOf, err := parseTimeQuery(c.Query("as_of"), true)
 	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid as_of: use RFC3339 or YYYY-MM-DD"})
+		return
+	}
+
+	item, err := ctrl.inventoryService.GetByID(id, asOf)
+	if err != nil {
+		if strings.Contains(err.Error(), "rebuild") {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusNotFound, gin.H{"error": "item not found"})
 		return
 	}
@@ -99,6 +124,239 @@ func (ctrl *InventoryController) GetByID(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": item})
 }
 
+// Export godoc
+// @Summary Export per-warehouse balances of all items as CSV
+// @Tags Inventory
+// @Security BearerAuth
+// @Produce text/csv
+// @Param as_of query string false "Balances as of RFC3339 time or end of YYYY-MM-DD"
+// @Success 200 {string} string "CSV file"
+// @Router /api/v1/inventory/export [get]
+func (ctrl *InventoryController) Export(c *gin.Context) {
+	asOf, err := parseTimeQuery(c.Query("as_of"), true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid as_of: use RFC3339 or YYYY-MM-DD"})
+		return
+	}
+
+	items, err := ctrl.inventoryService.GetBalances(asOf)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := "inventory.csv"
+	if raw := c.Query("as_of"); raw != "" {
+		filename = "inventory-as-of-" + strings.NewReplacer(":", "", "+", "").Replace(raw) + ".csv"
+	}
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	// One row per item and warehouse; items without any balance still get a row
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{"item_id", "sku", "item_name", "unit", "warehouse_id", "warehouse_code", "quantity"})
+	for _, item := range items {
+		if len(item.Stocks) == 0 {
+			_ = w.Write([]string{item.ID.String(), item.SKU, item.ItemName, item.Unit, "", "", "0"})
+			continue
+		}
+		for _, stock := range item.Stocks {
+			code := ""
+			if stock.Warehouse != nil {
+				code = stock.Warehouse.Code
+			}
+			_ = w.Write([]string{
+				item.ID.String(), item.SKU, item.ItemName, item.Unit,
+				stock.WarehouseID.String(), code, strconv.Itoa(stock.Quantity),
+			})
+		}
+	}
+	w.Flush()
+}
+
+// GetBins godoc
+// @Summary Get bin-level quantities of an inventory item (pick list)
+// @Tags Inventory
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Item ID"
+// @Param warehouse_id query string false "Warehouse ID filter"
+// @Success 200 {array} model.BinStock
+// @Router /api/v1/inventory/{id}/bins [get]
+func (ctrl *InventoryController) GetBins(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item ID"})
+		return
+	}
+
+	var warehouseID *uuid.UUID
+	if raw := c.Query("warehouse_id"); raw != "" {
+		parsed, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warehouse_id"})
+			return
+		}
+		warehouseID = &parsed
+	}
+
+	bins, err := ctrl.inventoryService.GetBins(id, warehouseID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": bins})
+}
+
+// GetMovements godoc
+// @Summary Get the stock movement ledger of an inventory item
+// @Tags Inventory
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Item ID"
+// @Param warehouse_id query string false "Warehouse ID filter"
+// @Param from query string false "Start of range, RFC3339 or YYYY-MM-DD (inclusive)"
+// @Param to query string false "End of range, RFC3339 (exclusive) or YYYY-MM-DD (inclusive day)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {array} model.StockMovement
+// @Router /api/v1/inventory/{id}/movements [get]
+func (ctrl *InventoryController) GetMovements(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item ID"})
+		return
+	}
+
+	var warehouseID *uuid.UUID
+	if raw := c.Query("warehouse_id"); raw != "" {
+		parsed, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warehouse_id"})
+			return
+		}
+		warehouseID = &parsed
+	}
+
+	from, err := parseTimeQuery(c.Query("from"), false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from: use RFC3339 or YYYY-MM-DD"})
+		return
+	}
+	to, err := parseTimeQuery(c.Query("to"), true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to: use RFC3339 or YYYY-MM-DD"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	movements, total, err := ctrl.inventoryService.GetMovements(id, warehouseID, from, to, page, limit)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			statusCode = http.StatusNotFound
+		case strings.Contains(err.Error(), "invalid date range"):
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  movements,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// GetExpiringLots godoc
+// @Summary List lots expiring within N days
+// @Tags Inventory
+// @Security BearerAuth
+// @Produce json
+// @Param days query int false "Days ahead" default(30)
+// @Param warehouse_id query string false "Warehouse ID filter"
+// @Success 200 {array} model.Lot
+// @Router /api/v1/lots/expiring [get]
+func (ctrl *InventoryController) GetExpiringLots(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid days"})
+		return
+	}
+
+	var warehouseID *uuid.UUID
+	if raw := c.Query("warehouse_id"); raw != "" {
+		parsed, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warehouse_id"})
+			return
+		}
+		warehouseID = &parsed
+	}
+
+	lots, err := ctrl.inventoryService.GetExpiringLots(days, warehouseID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "cannot be negative") {
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": lots,
+		"days": days,
+	})
+}
+
+// GetSerial godoc
+// @Summary Look up a serialized unit with its status and movement history
+// @Tags Inventory
+// @Security BearerAuth
+// @Produce json
+// @Param serial path string true "Serial number"
+// @Param item_id query string false "Item ID (needed when the serial exists for several items)"
+// @Success 200 {object} model.SerialNumber
+// @Router /api/v1/serials/{serial} [get]
+func (ctrl *InventoryController) GetSerial(c *gin.Context) {
+	var itemID *uuid.UUID
+	if raw := c.Query("item_id"); raw != "" {
+		parsed, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item_id"})
+			return
+		}
+		itemID = &parsed
+	}
+
+	unit, err := ctrl.inventoryService.GetSerial(c.Param("serial"), itemID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			statusCode = http.StatusNotFound
+		case strings.Contains(err.Error(), "several items"):
+			statusCode = http.StatusConflict
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": unit})
+}
+
 // Update godoc
 // @Summary Update an inventory item
 // @Tags Inventory
@@ -125,7 +383,11 @@ func (ctrl *InventoryController) Update(c *gin.Context) {
 	userID := middleware.GetUserID(c)
 	item, err := ctrl.inventoryService.Update(id, input, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "cannot change") {
+			statusCode = http.StatusConflict
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -134,3 +396,22 @@ func (ctrl *InventoryController) Update(c *gin.Context) {
 		"data":    item,
 	})
 }
+
+// parseTimeQuery parses an RFC3339 timestamp or a YYYY-MM-DD date (UTC). A bare date used
+// as an exclusive upper bound is moved to the next midnight so the whole day is included.
+func parseTimeQuery(raw string, endOfDay bool) (*time.Time, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
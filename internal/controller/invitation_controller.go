@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/dto"
+	"github.com/senoagung27/warehousex/internal/middleware"
+	"github.com/senoagung27/warehousex/internal/service"
+)
+
+type InvitationController struct {
+	invitationService service.InvitationServiceInterface
+}
+
+func NewInvitationController(invitationService service.InvitationServiceInterface) *InvitationController {
+	return &InvitationController{invitationService: invitationService}
+}
+
+// Create godoc
+// @Summary Invite a user with a fixed role; the token is only returned here
+// @Tags Invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body dto.CreateInvitationInput true "Create Invitation Input"
+// @Success 201 {object} dto.InvitationResponse
+// @Router /api/v1/invitations [post]
+func (ctrl *InvitationController) Create(c *gin.Context) {
+	var input dto.CreateInvitationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := ctrl.invitationService.Create(input, middleware.GetUserID(c))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "already registered") {
+			statusCode = http.StatusConflict
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "invitation created",
+		"data":    response,
+	})
+}
+
+// GetAll godoc
+// @Summary List invitations
+// @Tags Invitations
+// @Security BearerAuth
+// @Produce json
+// @Param pending query bool false "Only invitations that can still be accepted"
+// @Success 200 {array} model.Invitation
+// @Router /api/v1/invitations [get]
+func (ctrl *InvitationController) GetAll(c *gin.Context) {
+	invitations, err := ctrl.invitationService.GetAll(c.Query("pending") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": invitations})
+}
+
+// Revoke godoc
+// @Summary Revoke an invitation
+// @Tags Invitations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Invitation ID"
+// @Success 200 {object} model.Invitation
+// @Router /api/v1/invitations/{id} [delete]
+func (ctrl *InvitationController) Revoke(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid invitation ID"})
+		return
+	}
+
+	invitation, err := ctrl.invitationService.Revoke(id, middleware.GetUserID(c))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			statusCode = http.StatusNotFound
+		case strings.Contains(err.Error(), "already accepted"):
+			statusCode = http.StatusConflict
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "invitation revoked",
+		"data":    invitation,
+	})
+}
@@ -1,6 +1,8 @@
 package controller
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
@@ -27,6 +29,7 @@ func NewRequestController(requestService service.RequestServiceInterface) *Reque
 // @Produce json
 // @Security BearerAuth
 // @Param input body dto.CreateRequestInput true "Create Inbound Input"
+// @Param Idempotency-Key header string false "Replays the first response for repeated keys"
 // @Success 201 {object} model.Request
 // @Router /api/v1/requests/inbound [post]
 func (ctrl *RequestController) CreateInbound(c *gin.Context) {
@@ -56,6 +59,7 @@ func (ctrl *RequestController) CreateInbound(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param input body dto.CreateRequestInput true "Create Outbound Input"
+// @Param Idempotency-Key header string false "Replays the first response for repeated keys"
 // @Success 201 {object} model.Request
 // @Router /api/v1/requests/outbound [post]
 func (ctrl *RequestController) CreateOutbound(c *gin.Context) {
@@ -66,10 +70,10 @@ func (ctrl *RequestController) CreateOutbound(c *gin.Context) {
 	}
 
 	userID := middleware.GetUserID(c)
-	req, err := ctrl.requestService.CreateOutbound(input, userID)
+	req, err := ctrl.requestService.CreateOutbound(c.Request.Context(), input, userID)
 	if err != nil {
 		statusCode := http.StatusBadRequest
-		if strings.Contains(err.Error(), "insufficient stock") {
+		if strings.Contains(err.Error(), "insufficient stock") || strings.Contains(err.Error(), "lock conflict") {
 			statusCode = http.StatusConflict
 		}
 		c.JSON(statusCode, gin.H{"error": err.Error()})
@@ -82,6 +86,39 @@ func (ctrl *RequestController) CreateOutbound(c *gin.Context) {
 	})
 }
 
+// CreateTransfer godoc
+// @Summary Create inter-warehouse transfer request
+// @Tags Requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body dto.CreateTransferInput true "Create Transfer Input"
+// @Success 201 {object} model.Request
+// @Router /api/v1/requests/transfer [post]
+func (ctrl *RequestController) CreateTransfer(c *gin.Context) {
+	var input dto.CreateTransferInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	req, err := ctrl.requestService.CreateTransfer(c.Request.Context(), input, userID)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if strings.Contains(err.Error(), "insufficient stock") || strings.Contains(err.Error(), "lock conflict") {
+			statusCode = http.StatusConflict
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "transfer request created",
+		"data":    req,
+	})
+}
+
 // GetAll godoc
 // @Summary List all requests
 // @Tags Requests
@@ -89,7 +126,7 @@ func (ctrl *RequestController) CreateOutbound(c *gin.Context) {
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(20)
-// @Param type query string false "Request type (INBOUND/OUTBOUND)"
+// @Param type query string false "Request type (INBOUND/OUTBOUND/TRANSFER)"
 // @Param status query string false "Request status"
 // @Success 200 {array} model.Request
 // @Router /api/v1/requests [get]
@@ -142,7 +179,10 @@ func (ctrl *RequestController) GetByID(c *gin.Context) {
 // @Tags Requests
 // @Produce json
 // @Security BearerAuth
+// @Accept json
 // @Param id path string true "Request ID"
+// @Param input body dto.ApproveRequestInput false "Put-away / pick bins"
+// @Param Idempotency-Key header string false "Replays the first response for repeated keys"
 // @Success 200 {object} model.Request
 // @Router /api/v1/requests/{id}/approve [put]
 func (ctrl *RequestController) Approve(c *gin.Context) {
@@ -152,10 +192,17 @@ func (ctrl *RequestController) Approve(c *gin.Context) {
 		return
 	}
 
+	// The body is optional; an empty body approves without bin assignment
+	var input dto.ApproveRequestInput
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	userID := middleware.GetUserID(c)
 	userRole := middleware.GetUserRole(c)
 
-	req, err := ctrl.requestService.ApproveRequest(id, userID, userRole)
+	req, err := ctrl.requestService.ApproveRequest(c.Request.Context(), id, userID, userRole, input)
 	if err != nil {
 		statusCode := http.StatusBadRequest
 		errMsg := err.Error()
@@ -165,7 +212,7 @@ func (ctrl *RequestController) Approve(c *gin.Context) {
 			statusCode = http.StatusForbidden
 		case strings.Contains(errMsg, "not found"):
 			statusCode = http.StatusNotFound
-		case strings.Contains(errMsg, "lock conflict"):
+		case strings.Contains(errMsg, "lock conflict"), strings.Contains(errMsg, "approval conflict"):
 			statusCode = http.StatusConflict
 		case strings.Contains(errMsg, "insufficient stock"):
 			statusCode = http.StatusConflict
@@ -186,7 +233,10 @@ func (ctrl *RequestController) Approve(c *gin.Context) {
 // @Tags Requests
 // @Produce json
 // @Security BearerAuth
+// @Accept json
 // @Param id path string true "Request ID"
+// @Param input body dto.RejectRequestInput true "Rejection reason"
+// @Param Idempotency-Key header string false "Replays the first response for repeated keys"
 // @Success 200 {object} model.Request
 // @Router /api/v1/requests/{id}/reject [put]
 func (ctrl *RequestController) Reject(c *gin.Context) {
@@ -196,14 +246,25 @@ func (ctrl *RequestController) Reject(c *gin.Context) {
 		return
 	}
 
+	var input dto.RejectRequestInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	userID := middleware.GetUserID(c)
 	userRole := middleware.GetUserRole(c)
 
-	req, err := ctrl.requestService.RejectRequest(id, userID, userRole)
+	req, err := ctrl.requestService.RejectRequest(c.Request.Context(), id, userID, userRole, input)
 	if err != nil {
 		statusCode := http.StatusBadRequest
-		if strings.Contains(err.Error(), "insufficient permissions") {
+		switch {
+		case strings.Contains(err.Error(), "insufficient permissions"):
 			statusCode = http.StatusForbidden
+		case strings.Contains(err.Error(), "not found"):
+			statusCode = http.StatusNotFound
+		case strings.Contains(err.Error(), "lock conflict"):
+			statusCode = http.StatusConflict
 		}
 		c.JSON(statusCode, gin.H{"error": err.Error()})
 		return
@@ -214,3 +275,202 @@ func (ctrl *RequestController) Reject(c *gin.Context) {
 		"data":    req,
 	})
 }
+
+// BulkDecide godoc
+// @Summary Approve or reject several requests at once
+// @Description Each request is decided in its own transaction; the response reports the outcome per ID
+// @Tags Requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body dto.BulkDecisionInput true "Request IDs and decision"
+// @Success 200 {array} dto.BulkDecisionResult
+// @Router /api/v1/requests/bulk-decision [post]
+func (ctrl *RequestController) BulkDecide(c *gin.Context) {
+	var input dto.BulkDecisionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+
+	results := ctrl.requestService.BulkDecide(c.Request.Context(), input, userID, userRole)
+
+	succeeded := 0
+	for _, result := range results {
+		if result.Success {
+			succeeded++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "bulk decision processed",
+		"data":      results,
+		"succeeded": succeeded,
+		"failed":    len(results) - succeeded,
+	})
+}
+
+// Cancel godoc
+// @Summary Cancel a request (creator or admin)
+// @Tags Requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Request ID"
+// @Param input body dto.CancelRequestInput true "Cancellation reason"
+// @Success 200 {object} model.Request
+// @Router /api/v1/requests/{id}/cancel [put]
+func (ctrl *RequestController) Cancel(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request ID"})
+		return
+	}
+
+	var input dto.CancelRequestInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+
+	req, err := ctrl.requestService.CancelRequest(c.Request.Context(), id, userID, userRole, input)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		switch {
+		case strings.Contains(err.Error(), "insufficient permissions"):
+			statusCode = http.StatusForbidden
+		case strings.Contains(err.Error(), "not found"):
+			statusCode = http.StatusNotFound
+		case strings.Contains(err.Error(), "lock conflict"):
+			statusCode = http.StatusConflict
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "request cancelled",
+		"data":    req,
+	})
+}
+
+// Ship godoc
+// @Summary Record a shipment against an approved outbound request
+// @Tags Requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Request ID"
+// @Param input body dto.CreateShipmentInput true "Shipped lines"
+// @Success 201 {object} model.Request
+// @Router /api/v1/requests/{id}/shipments [post]
+func (ctrl *RequestController) Ship(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request ID"})
+		return
+	}
+
+	var input dto.CreateShipmentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	req, err := ctrl.requestService.ShipRequest(c.Request.Context(), id, userID, input)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			statusCode = http.StatusNotFound
+		case strings.Contains(err.Error(), "lock conflict"):
+			statusCode = http.StatusConflict
+		case strings.Contains(err.Error(), "insufficient stock"):
+			statusCode = http.StatusConflict
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "shipment recorded",
+		"data":    req,
+	})
+}
+
+// AddComment godoc
+// @Summary Comment on a request
+// @Tags Requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Request ID"
+// @Param input body dto.CreateCommentInput true "Comment"
+// @Success 201 {object} model.RequestComment
+// @Router /api/v1/requests/{id}/comments [post]
+func (ctrl *RequestController) AddComment(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request ID"})
+		return
+	}
+
+	var input dto.CreateCommentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+
+	comment, err := ctrl.requestService.AddComment(id, userID, userRole, input)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "comment added",
+		"data":    comment,
+	})
+}
+
+// GetComments godoc
+// @Summary List a request's comments, oldest first
+// @Tags Requests
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Request ID"
+// @Success 200 {array} model.RequestComment
+// @Router /api/v1/requests/{id}/comments [get]
+func (ctrl *RequestController) GetComments(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request ID"})
+		return
+	}
+
+	comments, err := ctrl.requestService.GetComments(id)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": comments})
+}
@@ -0,0 +1,232 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/dto"
+	"github.com/senoagung27/warehousex/internal/middleware"
+	"github.com/senoagung27/warehousex/internal/service"
+)
+
+type UserController struct {
+	userService service.UserServiceInterface
+}
+
+func NewUserController(userService service.UserServiceInterface) *UserController {
+	return &UserController{userService: userService}
+}
+
+// GetAll godoc
+// @Summary List users
+// @Tags Users
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param q query string false "Search name or email"
+// @Param role query string false "Filter by role"
+// @Param status query string false "Filter by status (active, pending, deactivated)"
+// @Success 200 {array} model.User
+// @Router /api/v1/users [get]
+func (ctrl *UserController) GetAll(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	search := c.Query("q")
+	role := c.Query("role")
+	status := c.Query("status")
+
+	users, total, err := ctrl.userService.GetAll(page, limit, search, role, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  users,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// Activate godoc
+// @Summary Activate a self-registered user or reactivate a deactivated one
+// @Tags Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} model.User
+// @Router /api/v1/users/{id}/activate [post]
+func (ctrl *UserController) Activate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	user, err := ctrl.userService.Activate(id, middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "user activated",
+		"data":    user,
+	})
+}
+
+// GetByID godoc
+// @Summary Get user by ID
+// @Tags Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} model.User
+// @Router /api/v1/users/{id} [get]
+func (ctrl *UserController) GetByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	user, err := ctrl.userService.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": user})
+}
+
+// UpdateRole godoc
+// @Summary Change a user's role
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param input body dto.UpdateUserRoleInput true "Update Role Input"
+// @Success 200 {object} model.User
+// @Router /api/v1/users/{id}/role [put]
+func (ctrl *UserController) UpdateRole(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	var input dto.UpdateUserRoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := ctrl.userService.UpdateRole(id, input, middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "role updated",
+		"data":    user,
+	})
+}
+
+// Deactivate godoc
+// @Summary Deactivate a user; their tokens stop working at once
+// @Tags Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} model.User
+// @Router /api/v1/users/{id}/deactivate [post]
+func (ctrl *UserController) Deactivate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	user, err := ctrl.userService.Deactivate(c.Request.Context(), id, middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "user deactivated",
+		"data":    user,
+	})
+}
+
+// Unlock godoc
+// @Summary Unlock an account locked after failed logins
+// @Tags Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} model.User
+// @Router /api/v1/users/{id}/unlock [post]
+func (ctrl *UserController) Unlock(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	user, err := ctrl.userService.Unlock(c.Request.Context(), id, middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "user unlocked",
+		"data":    user,
+	})
+}
+
+// ForcePasswordReset godoc
+// @Summary Force a password reset; the reset token is only returned here
+// @Tags Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.PasswordResetResponse
+// @Router /api/v1/users/{id}/reset-password [post]
+func (ctrl *UserController) ForcePasswordReset(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	response, err := ctrl.userService.ForcePasswordReset(c.Request.Context(), id, middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(userErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "password reset required",
+		"data":    response,
+	})
+}
+
+// userErrorStatus maps user service errors to HTTP statuses
+func userErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "cannot"):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
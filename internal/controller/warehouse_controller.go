@@ -0,0 +1,222 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/dto"
+	"github.com/senoagung27/warehousex/internal/middleware"
+	"github.com/senoagung27/warehousex/internal/service"
+)
+
+type WarehouseController struct {
+	warehouseService service.WarehouseServiceInterface
+}
+
+func NewWarehouseController(warehouseService service.WarehouseServiceInterface) *WarehouseController {
+	return &WarehouseController{warehouseService: warehouseService}
+}
+
+// Create godoc
+// @Summary Create a new warehouse
+// @Tags Warehouses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body dto.CreateWarehouseInput true "Create Warehouse Input"
+// @Success 201 {object} model.Warehouse
+// @Router /api/v1/warehouses [post]
+func (ctrl *WarehouseController) Create(c *gin.Context) {
+	var input dto.CreateWarehouseInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	warehouse, err := ctrl.warehouseService.Create(input, userID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "already exists") {
+			statusCode = http.StatusConflict
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "warehouse created",
+		"data":    warehouse,
+	})
+}
+
+// GetAll godoc
+// @Summary List all warehouses
+// @Tags Warehouses
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {array} model.Warehouse
+// @Router /api/v1/warehouses [get]
+func (ctrl *WarehouseController) GetAll(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	warehouses, total, err := ctrl.warehouseService.GetAll(page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  warehouses,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// GetByID godoc
+// @Summary Get warehouse by ID
+// @Tags Warehouses
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Warehouse ID"
+// @Success 200 {object} model.Warehouse
+// @Router /api/v1/warehouses/{id} [get]
+func (ctrl *WarehouseController) GetByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warehouse ID"})
+		return
+	}
+
+	warehouse, err := ctrl.warehouseService.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "warehouse not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": warehouse})
+}
+
+// Update godoc
+// @Summary Update a warehouse
+// @Tags Warehouses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Warehouse ID"
+// @Param input body dto.UpdateWarehouseInput true "Update Warehouse Input"
+// @Success 200 {object} model.Warehouse
+// @Router /api/v1/warehouses/{id} [put]
+func (ctrl *WarehouseController) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warehouse ID"})
+		return
+	}
+
+	var input dto.UpdateWarehouseInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	warehouse, err := ctrl.warehouseService.Update(id, input, userID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			statusCode = http.StatusNotFound
+		case strings.Contains(err.Error(), "already exists"):
+			statusCode = http.StatusConflict
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "warehouse updated",
+		"data":    warehouse,
+	})
+}
+
+// CreateLocation godoc
+// @Summary Create a zone, aisle, rack or bin inside a warehouse
+// @Tags Warehouses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Warehouse ID"
+// @Param input body dto.CreateLocationInput true "Create Location Input"
+// @Success 201 {object} model.Location
+// @Router /api/v1/warehouses/{id}/locations [post]
+func (ctrl *WarehouseController) CreateLocation(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warehouse ID"})
+		return
+	}
+
+	var input dto.CreateLocationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	location, err := ctrl.warehouseService.CreateLocation(id, input, userID)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		switch {
+		case strings.Contains(err.Error(), "warehouse not found"):
+			statusCode = http.StatusNotFound
+		case strings.Contains(err.Error(), "already exists"):
+			statusCode = http.StatusConflict
+		case strings.Contains(err.Error(), "failed to"):
+			statusCode = http.StatusInternalServerError
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "location created",
+		"data":    location,
+	})
+}
+
+// GetLocations godoc
+// @Summary List the locations of a warehouse
+// @Tags Warehouses
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Warehouse ID"
+// @Param type query string false "Location type (ZONE/AISLE/RACK/BIN)"
+// @Success 200 {array} model.Location
+// @Router /api/v1/warehouses/{id}/locations [get]
+func (ctrl *WarehouseController) GetLocations(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warehouse ID"})
+		return
+	}
+
+	locations, err := ctrl.warehouseService.GetLocations(id, c.Query("type"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": locations})
+}
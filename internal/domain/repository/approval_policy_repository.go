@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/model"
+)
+
+type ApprovalPolicyRepository interface {
+	Create(policy *model.ApprovalPolicy) error
+	FindByID(id uuid.UUID) (*model.ApprovalPolicy, error)
+	// FindAll returns policies with their steps, oldest first
+	FindAll(activeOnly bool) ([]model.ApprovalPolicy, error)
+	Update(policy *model.ApprovalPolicy) error
+}
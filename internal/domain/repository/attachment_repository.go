@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/model"
+)
+
+type AttachmentRepository interface {
+	CreateWithTx(tx interface{}, attachment *model.RequestAttachment) error
+	// FindByID returns an attachment of the given request
+	FindByID(requestID, id uuid.UUID) (*model.RequestAttachment, error)
+	// FindByRequest returns a request's attachments, oldest first
+	FindByRequest(requestID uuid.UUID) ([]model.RequestAttachment, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/model"
+)
+
+type DelegationRepository interface {
+	Create(delegation *model.ApprovalDelegation) error
+	FindByID(id uuid.UUID) (*model.ApprovalDelegation, error)
+	// FindByUser returns delegations the user gave or received; a nil user returns all
+	FindByUser(userID *uuid.UUID) ([]model.ApprovalDelegation, error)
+	// FindActiveForDelegate returns unrevoked delegations to the user covering the given time,
+	// with their delegators
+	FindActiveForDelegate(delegateID uuid.UUID, at time.Time) ([]model.ApprovalDelegation, error)
+	Update(delegation *model.ApprovalDelegation) error
+}
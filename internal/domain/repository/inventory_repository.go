@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/senoagung27/warehousex/internal/model"
 )
@@ -8,7 +10,10 @@ import (
 type InventoryRepository interface {
 	Create(item *model.Inventory) error
 	FindByID(id uuid.UUID) (*model.Inventory, error)
-	FindAll(page, limit int) ([]model.Inventory, int64, error)
+	// FindAll lists items; createdBefore, when set, hides items created at or after it
+	FindAll(page, limit int, createdBefore *time.Time) ([]model.Inventory, int64, error)
+	// FindAllWithStocks lists every item with its per-warehouse balances, ordered by SKU
+	FindAllWithStocks(createdBefore *time.Time) ([]model.Inventory, error)
 	Update(item *model.Inventory) error
 	// FindByIDForUpdate uses SELECT FOR UPDATE row-level locking
 	FindByIDForUpdate(tx interface{}, id uuid.UUID) (*model.Inventory, error)
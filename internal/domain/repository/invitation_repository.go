@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/model"
+)
+
+type InvitationRepository interface {
+	Create(invitation *model.Invitation) error
+	FindByID(id uuid.UUID) (*model.Invitation, error)
+	FindByTokenHash(tokenHash string) (*model.Invitation, error)
+	// FindAll returns invitations newest first; pendingOnly drops accepted, revoked
+	// and expired ones
+	FindAll(pendingOnly bool) ([]model.Invitation, error)
+	Update(invitation *model.Invitation) error
+	// AcceptWithTx marks the invitation accepted by the user; it fails if the
+	// invitation was already accepted or revoked, so each token is used once
+	AcceptWithTx(tx interface{}, id, userID uuid.UUID, at time.Time) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/model"
+)
+
+type LocationRepository interface {
+	Create(location *model.Location) error
+	FindByID(id uuid.UUID) (*model.Location, error)
+	FindByWarehouse(warehouseID uuid.UUID, locationType string) ([]model.Location, error)
+	FindByPath(warehouseID uuid.UUID, path string) (*model.Location, error)
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/model"
+)
+
+type LotRepository interface {
+	FindByItem(itemID uuid.UUID) ([]model.Lot, error)
+	// FindExpiring returns lots with stock left that expire before the given time
+	FindExpiring(before time.Time, warehouseID *uuid.UUID) ([]model.Lot, error)
+	// FindOrCreateForUpdate creates the lot if it does not exist yet, then locks it
+	FindOrCreateForUpdate(tx interface{}, lot *model.Lot) (*model.Lot, error)
+	// FindAvailableForUpdate locks an item's unexpired lots at a warehouse in FEFO order
+	FindAvailableForUpdate(tx interface{}, itemID, warehouseID uuid.UUID) ([]model.Lot, error)
+	// SumQuantityWithTx returns how much of an item/warehouse balance is held in lots,
+	// expired ones included
+	SumQuantityWithTx(tx interface{}, itemID, warehouseID uuid.UUID) (int, error)
+	UpdateWithTx(tx interface{}, lot *model.Lot) error
+	CreateAllocationsWithTx(tx interface{}, allocations []model.RequestLot) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/model"
+)
+
+type MovementRepository interface {
+	// CreateWithTx appends a ledger entry within the transaction that changed the balance
+	CreateWithTx(tx interface{}, movement *model.StockMovement) error
+	// FindByItem lists an item's movements, newest first; from is inclusive and to exclusive
+	FindByItem(page, limit int, itemID uuid.UUID, warehouseID *uuid.UUID, from, to *time.Time) ([]model.StockMovement, int64, error)
+	// BalancesAsOf returns the balance of each stock row, keyed by stock ID, after the last
+	// movement before asOf. Stock rows with no earlier movement are absent. A nil itemIDs
+	// covers every item.
+	BalancesAsOf(itemIDs []uuid.UUID, asOf time.Time) (map[uuid.UUID]int, error)
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/model"
+)
+
+// ErrRefreshTokenUsed is returned by MarkUsedWithTx when the token was already
+// exchanged or revoked
+var ErrRefreshTokenUsed = errors.New("refresh token already used")
+
+type RefreshTokenRepository interface {
+	Create(token *model.RefreshToken) error
+	CreateWithTx(tx interface{}, token *model.RefreshToken) error
+	FindByTokenHash(tokenHash string) (*model.RefreshToken, error)
+	FindByAccessJTI(jti string) (*model.RefreshToken, error)
+	// MarkUsedWithTx marks the token used; it fails if the token was already used or
+	// revoked (ErrRefreshTokenUsed), so each token is exchanged once
+	MarkUsedWithTx(tx interface{}, id uuid.UUID, at time.Time) error
+	// RevokeFamily revokes every token in the family and returns those whose access
+	// token has not expired yet
+	RevokeFamily(familyID uuid.UUID, at time.Time) ([]model.RefreshToken, error)
+	// RevokeByUser revokes every token of the user and returns those whose access
+	// token has not expired yet
+	RevokeByUser(userID uuid.UUID, at time.Time) ([]model.RefreshToken, error)
+}
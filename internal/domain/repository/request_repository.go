@@ -1,15 +1,33 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/senoagung27/warehousex/internal/model"
 )
 
 type RequestRepository interface {
 	Create(req *model.Request) error
+	CreateWithTx(tx interface{}, req *model.Request) error
 	FindByID(id uuid.UUID) (*model.Request, error)
 	FindAll(page, limit int, filters map[string]interface{}) ([]model.Request, int64, error)
 	Update(req *model.Request) error
+	// FindPendingBefore returns up to limit pending requests of a type created before
+	// the given time, oldest first; unescalatedOnly skips ones already escalated
+	FindPendingBefore(reqType string, before time.Time, unescalatedOnly bool, limit int) ([]model.Request, error)
+	// MarkEscalatedWithTx sets escalated_at on the request if it is still pending and
+	// unescalated, and reports whether it did
+	MarkEscalatedWithTx(tx interface{}, id uuid.UUID, at time.Time) (bool, error)
 	FindByIDWithTx(tx interface{}, id uuid.UUID) (*model.Request, error)
+	// FindByIDForUpdate locks the request row so status changes are serialized
+	FindByIDForUpdate(tx interface{}, id uuid.UUID) (*model.Request, error)
 	UpdateWithTx(tx interface{}, req *model.Request) error
+	SignApprovalWithTx(tx interface{}, approval *model.RequestApproval) error
+	CreateShipmentWithTx(tx interface{}, shipment *model.Shipment) error
+	// ShipSerialsWithTx marks unshipped serial numbers of a request line as shipped
+	ShipSerialsWithTx(tx interface{}, lineID, shipmentID uuid.UUID, serials []string) error
+	CreateCommentWithTx(tx interface{}, comment *model.RequestComment) error
+	// FindComments returns a request's comment thread, oldest first
+	FindComments(requestID uuid.UUID) ([]model.RequestComment, error)
 }
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/model"
+)
+
+type SerialRepository interface {
+	// FindBySerial returns every unit with this serial number, with its movement history
+	FindBySerial(serial string, itemID *uuid.UUID) ([]model.SerialNumber, error)
+	FindByItemAndSerials(itemID uuid.UUID, serials []string) ([]model.SerialNumber, error)
+	// FindForUpdate locks the given units of an item in serial number order
+	FindForUpdate(tx interface{}, itemID uuid.UUID, serials []string) ([]model.SerialNumber, error)
+	SaveWithTx(tx interface{}, serial *model.SerialNumber) error
+	CreateMovementsWithTx(tx interface{}, movements []model.SerialMovement) error
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/model"
+)
+
+type StockRepository interface {
+	FindByItem(itemID uuid.UUID) ([]model.InventoryStock, error)
+	FindByItemAndWarehouse(itemID, warehouseID uuid.UUID) (*model.InventoryStock, error)
+	// FindForUpdate uses SELECT FOR UPDATE to lock a single item/warehouse balance
+	FindForUpdate(tx interface{}, itemID, warehouseID uuid.UUID) (*model.InventoryStock, error)
+	// FindOrCreateForUpdate creates an empty balance if none exists, then locks it
+	FindOrCreateForUpdate(tx interface{}, itemID, warehouseID uuid.UUID) (*model.InventoryStock, error)
+	// UpdateWithTx updates within an existing transaction
+	UpdateWithTx(tx interface{}, stock *model.InventoryStock) error
+
+	// Bin-level balances. Callers must already hold the item/warehouse balance lock.
+	FindBinsByItem(itemID uuid.UUID, warehouseID *uuid.UUID) ([]model.BinStock, error)
+	FindBinForUpdate(tx interface{}, itemID, locationID uuid.UUID) (*model.BinStock, error)
+	FindOrCreateBinForUpdate(tx interface{}, itemID, warehouseID, locationID uuid.UUID) (*model.BinStock, error)
+	UpdateBinWithTx(tx interface{}, bin *model.BinStock) error
+	// SumBinnedWithTx returns how much of an item/warehouse balance is assigned to bins
+	SumBinnedWithTx(tx interface{}, itemID, warehouseID uuid.UUID) (int, error)
+}
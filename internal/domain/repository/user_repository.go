@@ -1,13 +1,24 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/senoagung27/warehousex/internal/model"
 )
 
 type UserRepository interface {
 	Create(user *model.User) error
+	CreateWithTx(tx interface{}, user *model.User) error
 	FindByID(id uuid.UUID) (*model.User, error)
 	FindByEmail(email string) (*model.User, error)
-	FindAll(page, limit int) ([]model.User, int64, error)
+	FindByResetTokenHash(tokenHash string) (*model.User, error)
+	// FindAll returns a page of users whose name or email contains search, filtered
+	// by role and status when they are set
+	FindAll(page, limit int, search, role, status string) ([]model.User, int64, error)
+	FindByRole(role string) ([]model.User, error)
+	Update(user *model.User) error
+	// SetLockedUntil writes only the lockout of a user, leaving concurrent changes to
+	// the rest of the row alone
+	SetLockedUntil(id uuid.UUID, lockedUntil *time.Time) error
 }
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/model"
+)
+
+type WarehouseRepository interface {
+	Create(warehouse *model.Warehouse) error
+	FindByID(id uuid.UUID) (*model.Warehouse, error)
+	FindByCode(code string) (*model.Warehouse, error)
+	FindAll(page, limit int) ([]model.Warehouse, int64, error)
+	Update(warehouse *model.Warehouse) error
+}
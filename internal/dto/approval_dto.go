@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+// CreateApprovalPolicyInput defines an approval chain. Empty request_type or
+// category match any; steps are the roles that must sign, in order.
+type CreateApprovalPolicyInput struct {
+	Name         string   `json:"name" binding:"required"`
+	RequestType  string   `json:"request_type" binding:"omitempty,oneof=INBOUND OUTBOUND TRANSFER"`
+	Category     string   `json:"category"`
+	QuantityOver int      `json:"quantity_over" binding:"min=0"`
+	ValueOver    float64  `json:"value_over" binding:"min=0"`
+	Steps        []string `json:"steps" binding:"required,min=1,max=5,dive,oneof=supervisor admin"`
+}
+
+// CreateDelegationInput hands the caller's approval authority to another user for
+// [starts_at, ends_at); request_types limits it to those types when given
+type CreateDelegationInput struct {
+	DelegateID   string    `json:"delegate_id" binding:"required,uuid"`
+	StartsAt     time.Time `json:"starts_at" binding:"required"`
+	EndsAt       time.Time `json:"ends_at" binding:"required,gtfield=StartsAt"`
+	RequestTypes []string  `json:"request_types" binding:"omitempty,max=3,dive,oneof=INBOUND OUTBOUND TRANSFER"`
+	Reason       string    `json:"reason"`
+}
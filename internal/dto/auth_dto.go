@@ -1,12 +1,17 @@
 package dto
 
-import "github.com/senoagung27/warehousex/internal/model"
+import (
+	"time"
 
+	"github.com/senoagung27/warehousex/internal/model"
+)
+
+// RegisterInput is a public sign-up; the account is created as staff pending admin
+// activation. Other roles are only granted through invitations.
 type RegisterInput struct {
 	Name     string `json:"name" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
-	Role     string `json:"role" binding:"required,oneof=staff supervisor admin auditor"`
 }
 
 type LoginInput struct {
@@ -14,7 +19,54 @@ type LoginInput struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// AuthResponse carries a short-lived access token and the refresh token that
+// replaces it; each refresh token can be exchanged once
 type AuthResponse struct {
-	Token string     `json:"token"`
-	User  model.User `json:"user"`
+	Token        string     `json:"token"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	RefreshToken string     `json:"refresh_token"`
+	User         model.User `json:"user"`
+}
+
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutInput optionally names the refresh token to revoke; by default the session
+// of the access token used is ended
+type LogoutInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// CreateInvitationInput invites an email address to sign up with a fixed role
+type CreateInvitationInput struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=staff supervisor admin auditor"`
+}
+
+// InvitationResponse carries the invitation token; it is only shown once
+type InvitationResponse struct {
+	Invitation model.Invitation `json:"invitation"`
+	Token      string           `json:"token"`
+}
+
+// AcceptInvitationInput creates the invited account; email and role come from the
+// invitation
+type AcceptInvitationInput struct {
+	Token    string `json:"token" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// ResetPasswordInput sets a new password with a reset token issued by an admin
+type ResetPasswordInput struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// BootstrapAdminInput creates the first admin from the command line
+type BootstrapAdminInput struct {
+	Name     string
+	Email    string
+	Password string
 }
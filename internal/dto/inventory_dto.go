@@ -5,10 +5,20 @@ type CreateInventoryInput struct {
 	SKU      string `json:"sku" binding:"required"`
 	Quantity int    `json:"quantity" binding:"min=0"`
 	Unit     string `json:"unit" binding:"required"`
+	// Category and UnitValue are matched by approval policies
+	Category  string  `json:"category"`
+	UnitValue float64 `json:"unit_value" binding:"min=0"`
+	// Serialized items cannot be created with opening stock; receive them with serial numbers instead
+	Serialized bool `json:"serialized"`
+	// WarehouseID receives the opening quantity; required when quantity > 0
+	WarehouseID string `json:"warehouse_id" binding:"omitempty,uuid"`
 }
 
 type UpdateInventoryInput struct {
-	ItemName string `json:"item_name"`
-	SKU      string `json:"sku"`
-	Unit     string `json:"unit"`
+	ItemName   string   `json:"item_name"`
+	SKU        string   `json:"sku"`
+	Unit       string   `json:"unit"`
+	Category   string   `json:"category"`
+	UnitValue  *float64 `json:"unit_value" binding:"omitempty,min=0"`
+	Serialized *bool    `json:"serialized"`
 }
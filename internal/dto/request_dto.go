@@ -1,7 +1,112 @@
 package dto
 
+import "time"
+
+// CreateRequestInput creates an inbound or outbound request. Multi-item requests
+// list their items in Lines; single-item clients may instead give the item fields
+// at the top level, which become the only line.
 type CreateRequestInput struct {
-	ItemID   string `json:"item_id" binding:"required,uuid"`
-	Quantity int    `json:"quantity" binding:"required,min=1"`
-	Notes    string `json:"notes"`
+	ItemID      string `json:"item_id" binding:"required_without=Lines,omitempty,uuid"`
+	WarehouseID string `json:"warehouse_id" binding:"required,uuid"`
+	Quantity    int    `json:"quantity" binding:"required_without=Lines,omitempty,min=1"`
+	Notes       string `json:"notes"`
+	// Lot received by an inbound request (ignored for outbound); dates are RFC 3339
+	LotNumber      string     `json:"lot_number"`
+	ManufacturedAt *time.Time `json:"manufactured_at"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	// SerialNumbers is required for serialized items: exactly Quantity unique serials
+	SerialNumbers []string           `json:"serial_numbers"`
+	Lines         []RequestLineInput `json:"lines" binding:"omitempty,max=200,dive"`
+}
+
+// RequestLineInput is one item of a request; its fields mean the same as the
+// top-level item fields of CreateRequestInput
+type RequestLineInput struct {
+	ItemID         string     `json:"item_id" binding:"required,uuid"`
+	Quantity       int        `json:"quantity" binding:"required,min=1"`
+	LotNumber      string     `json:"lot_number"`
+	ManufacturedAt *time.Time `json:"manufactured_at"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	SerialNumbers  []string   `json:"serial_numbers"`
+}
+
+type CreateTransferInput struct {
+	ItemID                 string             `json:"item_id" binding:"required_without=Lines,omitempty,uuid"`
+	SourceWarehouseID      string             `json:"source_warehouse_id" binding:"required,uuid"`
+	DestinationWarehouseID string             `json:"destination_warehouse_id" binding:"required,uuid"`
+	Quantity               int                `json:"quantity" binding:"required_without=Lines,omitempty,min=1"`
+	Notes                  string             `json:"notes"`
+	SerialNumbers          []string           `json:"serial_numbers"`
+	Lines                  []RequestLineInput `json:"lines" binding:"omitempty,max=200,dive"`
+}
+
+// ApproveRequestInput is the optional approval body. BinID is the put-away bin
+// for inbound and the pick bin for outbound or transfer requests;
+// DestinationBinID is the put-away bin at a transfer's destination. The
+// top-level bins apply to every line unless Lines overrides them per line.
+type ApproveRequestInput struct {
+	BinID            string             `json:"bin_id" binding:"omitempty,uuid"`
+	DestinationBinID string             `json:"destination_bin_id" binding:"omitempty,uuid"`
+	Lines            []ApproveLineInput `json:"lines" binding:"omitempty,dive"`
+}
+
+// ApproveLineInput chooses the bins of one request line
+type ApproveLineInput struct {
+	LineID           string `json:"line_id" binding:"required,uuid"`
+	BinID            string `json:"bin_id" binding:"omitempty,uuid"`
+	DestinationBinID string `json:"destination_bin_id" binding:"omitempty,uuid"`
+}
+
+// Decisions of a bulk approval
+const (
+	DecisionApprove = "approve"
+	DecisionReject  = "reject"
+)
+
+// BulkDecisionInput approves or rejects several requests at once. Approvals take no
+// bins, so requests that need a bin choice should be approved one by one. Reason is
+// required to reject and applies to every request.
+type BulkDecisionInput struct {
+	RequestIDs []string `json:"request_ids" binding:"required,min=1,max=100,dive,uuid"`
+	Decision   string   `json:"decision" binding:"required,oneof=approve reject"`
+	Reason     string   `json:"reason" binding:"required_if=Decision reject,max=500"`
+}
+
+// BulkDecisionResult is the outcome for one request of a bulk decision
+type BulkDecisionResult struct {
+	RequestID string `json:"request_id"`
+	Success   bool   `json:"success"`
+	Status    string `json:"status,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// RejectRequestInput is the body of a rejection; the reason is kept on the request
+type RejectRequestInput struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// CreateCommentInput adds a message to a request's comment thread
+type CreateCommentInput struct {
+	Body string `json:"body" binding:"required,max=2000"`
+}
+
+// CancelRequestInput is the body of a cancellation; the reason goes to the audit log
+type CancelRequestInput struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// CreateShipmentInput records what was picked and shipped for an approved outbound
+// request. A line shipped short stays open for a later shipment.
+type CreateShipmentInput struct {
+	Notes string              `json:"notes"`
+	Lines []ShipmentLineInput `json:"lines" binding:"required,min=1,max=200,dive"`
+}
+
+// ShipmentLineInput is the quantity shipped of one request line. BinID overrides the
+// pick bin chosen at approval; serialized lines name the units shipped.
+type ShipmentLineInput struct {
+	LineID        string   `json:"line_id" binding:"required,uuid"`
+	Quantity      int      `json:"quantity" binding:"required,min=1"`
+	BinID         string   `json:"bin_id" binding:"omitempty,uuid"`
+	SerialNumbers []string `json:"serial_numbers"`
 }
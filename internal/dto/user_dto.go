@@ -0,0 +1,14 @@
+package dto
+
+import "github.com/senoagung27/warehousex/internal/model"
+
+type UpdateUserRoleInput struct {
+	Role string `json:"role" binding:"required,oneof=staff supervisor admin auditor"`
+}
+
+// PasswordResetResponse carries the reset token for the admin to pass on; it is
+// only shown once
+type PasswordResetResponse struct {
+	User  model.User `json:"user"`
+	Token string     `json:"token"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+type CreateWarehouseInput struct {
+	Code    string `json:"code" binding:"required"`
+	Name    string `json:"name" binding:"required"`
+	Address string `json:"address"`
+}
+
+type UpdateWarehouseInput struct {
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+type CreateLocationInput struct {
+	ParentID string `json:"parent_id" binding:"omitempty,uuid"`
+	Type     string `json:"type" binding:"required,oneof=ZONE AISLE RACK BIN"`
+	Code     string `json:"code" binding:"required"`
+	Name     string `json:"name"`
+}
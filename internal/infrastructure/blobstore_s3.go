@@ -0,0 +1,212 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/senoagung27/warehousex/internal/config"
+)
+
+// unsignedPayload tells S3 the body is not part of the signature, so uploads can
+// be streamed without hashing them first
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3BlobStore keeps blobs in an S3-compatible bucket (AWS S3, MinIO). Requests use
+// path-style URLs and Signature Version 4.
+type S3BlobStore struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	client    *http.Client
+}
+
+func NewS3BlobStore(cfg *config.StorageConfig) (*S3BlobStore, error) {
+	endpoint, err := url.Parse(cfg.S3Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint: %s", cfg.S3Endpoint)
+	}
+	if cfg.S3Bucket == "" || cfg.S3AccessKey == "" || cfg.S3SecretKey == "" {
+		return nil, errors.New("S3 bucket and credentials are required")
+	}
+
+	return &S3BlobStore{
+		endpoint:  endpoint,
+		region:    cfg.S3Region,
+		bucket:    cfg.S3Bucket,
+		accessKey: cfg.S3AccessKey,
+		secretKey: cfg.S3SecretKey,
+		client:    &http.Client{Timeout: 5 * time.Minute},
+	}, nil
+}
+
+func (s *S3BlobStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := s.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload blob: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3BlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *S3BlobStore) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req)
+	if err != nil && !errors.Is(err, ErrBlobNotFound) {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	if resp != nil {
+		resp.Body.Close()
+	}
+	return nil
+}
+
+// newRequest builds a request for an object of the bucket
+func (s *S3BlobStore) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	segments := append([]string{s.bucket}, strings.Split(strings.Trim(key, "/"), "/")...)
+	escaped := make([]string, len(segments))
+	for i, segment := range segments {
+		escaped[i] = url.PathEscape(segment)
+	}
+
+	base := strings.TrimSuffix(s.endpoint.Path, "/")
+	target := *s.endpoint
+	target.Path = base + "/" + strings.Join(segments, "/")
+	target.RawPath = base + "/" + strings.Join(escaped, "/")
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build S3 request: %w", err)
+	}
+	return req, nil
+}
+
+// do signs and sends a request. Non-2xx responses become errors; a missing object
+// is ErrBlobNotFound.
+func (s *S3BlobStore) do(req *http.Request) (*http.Response, error) {
+	s.sign(req, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrBlobNotFound
+	}
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("S3 %s returned %d: %s", req.Method, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return resp, nil
+}
+
+// sign adds a Signature Version 4 Authorization header covering the host and the
+// x-amz-* headers
+func (s *S3BlobStore) sign(req *http.Request, now time.Time) {
+	req.Header.Set("x-amz-date", now.Format(amzDateFormat))
+	req.Header.Set("x-amz-content-sha256", unsignedPayload)
+	signV4(req, s.accessKey, s.secretKey, s.region, "s3", now, unsignedPayload, "x-amz-content-sha256", "x-amz-date")
+}
+
+// amzDateFormat is the timestamp format of x-amz-date
+const amzDateFormat = "20060102T150405Z"
+
+// signV4 sets the Signature Version 4 Authorization header of a request. The
+// signature covers the host and the named headers, which must already be set, and
+// payloadHash stands for the body.
+func signV4(req *http.Request, accessKey, secretKey, region, service string, now time.Time, payloadHash string, headers ...string) {
+	amzDate := now.Format(amzDateFormat)
+	date := now.Format("20060102")
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	names := []string{"host"}
+	for _, header := range headers {
+		names = append(names, strings.ToLower(header))
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		value := host
+		if name != "host" {
+			value = strings.TrimSpace(req.Header.Get(name))
+		}
+		canonicalHeaders.WriteString(name + ":" + value + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := signingKey(secretKey, date, region, service)
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature,
+	))
+}
+
+// signingKey derives the Signature Version 4 key for one day, region and service
+func signingKey(secretKey, date, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,55 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// IdempotentResponse is the response stored for an Idempotency-Key. Fingerprint
+// identifies the request that produced it, so a key reused for another request
+// can be refused.
+type IdempotentResponse struct {
+	Fingerprint string `json:"fingerprint"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// IdempotencyKey returns the Redis key holding the response for a caller's key
+func IdempotencyKey(userID, key string) string {
+	return fmt.Sprintf("idempotency:%s:%s", userID, key)
+}
+
+// GetIdempotentResponse returns the response stored under key, or nil if none is
+func (r *RedisClient) GetIdempotentResponse(ctx context.Context, key string) (*IdempotentResponse, error) {
+	data, err := r.Client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read idempotent response: %w", err)
+	}
+
+	var resp IdempotentResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode idempotent response: %w", err)
+	}
+	return &resp, nil
+}
+
+// SaveIdempotentResponse stores a response under key until ttl passes
+func (r *RedisClient) SaveIdempotentResponse(ctx context.Context, key string, resp *IdempotentResponse, ttl time.Duration) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("failed to encode idempotent response: %w", err)
+	}
+	if err := r.Client.Set(ctx, key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save idempotent response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package infrastructure
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/senoagung27/warehousex/internal/config"
+)
+
+// minRSABits is the smallest RSA key accepted for signing tokens
+const minRSABits = 2048
+
+// JWK is a public key in JSON Web Key form
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is the document served at /.well-known/jwks.json
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+type jwtKey struct {
+	method jwt.SigningMethod
+	sign   interface{}
+	verify interface{}
+}
+
+// KeySet holds the keys tokens are signed and verified with. Asymmetric keys are
+// loaded from PEM files and identified by their file name, which becomes the kid
+// header: tokens are signed with one private key and verified with any key in the
+// set, so a new key can sign while tokens from the previous one still verify. A
+// public-key PEM keeps a retired key verifiable. Without key files, tokens are
+// signed with the HMAC secret and no keys are published.
+type KeySet struct {
+	signingKID string
+	keys       map[string]jwtKey
+	jwks       JWKS
+}
+
+// NewKeySet loads the signing keys configured in cfg
+func NewKeySet(cfg *config.JWTConfig) (*KeySet, error) {
+	if len(cfg.KeyFiles) == 0 {
+		return &KeySet{
+			keys: map[string]jwtKey{
+				"": {method: jwt.SigningMethodHS256, sign: []byte(cfg.Secret), verify: []byte(cfg.Secret)},
+			},
+			jwks: JWKS{Keys: []JWK{}},
+		}, nil
+	}
+
+	ks := &KeySet{keys: make(map[string]jwtKey), jwks: JWKS{Keys: []JWK{}}}
+	for _, path := range cfg.KeyFiles {
+		kid := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		if _, ok := ks.keys[kid]; ok {
+			return nil, fmt.Errorf("duplicate JWT key id %q", kid)
+		}
+
+		key, jwk, err := loadJWTKey(path)
+		if err != nil {
+			return nil, err
+		}
+		jwk.Kid = kid
+		ks.keys[kid] = key
+		ks.jwks.Keys = append(ks.jwks.Keys, jwk)
+
+		if ks.signingKID == "" && cfg.SigningKeyID == "" && key.sign != nil {
+			ks.signingKID = kid
+		}
+	}
+
+	if cfg.SigningKeyID != "" {
+		key, ok := ks.keys[cfg.SigningKeyID]
+		if !ok || key.sign == nil {
+			return nil, fmt.Errorf("no private key with id %q among JWT_KEY_FILES", cfg.SigningKeyID)
+		}
+		ks.signingKID = cfg.SigningKeyID
+	}
+	if ks.signingKID == "" {
+		return nil, errors.New("JWT_KEY_FILES holds no private key to sign with")
+	}
+
+	return ks, nil
+}
+
+// SigningKeyID returns the kid new tokens carry, empty for the HMAC secret
+func (ks *KeySet) SigningKeyID() string {
+	return ks.signingKID
+}
+
+// Algorithm returns the algorithm new tokens are signed with
+func (ks *KeySet) Algorithm() string {
+	return ks.keys[ks.signingKID].method.Alg()
+}
+
+// Sign signs the claims with the signing key
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	key := ks.keys[ks.signingKID]
+	token := jwt.NewWithClaims(key.method, claims)
+	if ks.signingKID != "" {
+		token.Header["kid"] = ks.signingKID
+	}
+	return token.SignedString(key.sign)
+}
+
+// Keyfunc returns the key to verify a token with, chosen by its kid header; the
+// token's algorithm must be the key's
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, jwt.ErrSignatureInvalid
+	}
+	return key.verify, nil
+}
+
+// Methods returns the algorithms of the keys in the set
+func (ks *KeySet) Methods() []string {
+	seen := make(map[string]bool)
+	var methods []string
+	for _, key := range ks.keys {
+		if alg := key.method.Alg(); !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
+}
+
+// JWKS returns the public keys of the set
+func (ks *KeySet) JWKS() JWKS {
+	return ks.jwks
+}
+
+// loadJWTKey reads an RSA or Ed25519 key from a PEM file. A private key can sign
+// and verify, a public key only verify.
+func loadJWTKey(path string) (jwtKey, JWK, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return jwtKey{}, JWK{}, fmt.Errorf("failed to read JWT key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return jwtKey{}, JWK{}, fmt.Errorf("%s: no PEM block found", path)
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return jwtKey{}, JWK{}, fmt.Errorf("%s: unsupported PEM block %q", path, block.Type)
+	}
+	if err != nil {
+		return jwtKey{}, JWK{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	var key jwtKey
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key = jwtKey{method: jwt.SigningMethodRS256, sign: k, verify: &k.PublicKey}
+	case *rsa.PublicKey:
+		key = jwtKey{method: jwt.SigningMethodRS256, verify: k}
+	case ed25519.PrivateKey:
+		key = jwtKey{method: jwt.SigningMethodEdDSA, sign: k, verify: k.Public()}
+	case ed25519.PublicKey:
+		key = jwtKey{method: jwt.SigningMethodEdDSA, verify: k}
+	default:
+		return jwtKey{}, JWK{}, fmt.Errorf("%s: only RSA and Ed25519 keys are supported", path)
+	}
+
+	var jwk JWK
+	switch pub := key.verify.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSABits {
+			return jwtKey{}, JWK{}, fmt.Errorf("%s: RSA keys must be at least %d bits", path, minRSABits)
+		}
+		jwk = JWK{
+			Kty: "RSA",
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}
+	case ed25519.PublicKey:
+		jwk = JWK{Kty: "OKP", Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(pub)}
+	}
+	jwk.Use = "sig"
+	jwk.Alg = key.method.Alg()
+
+	return key, jwk, nil
+}
@@ -0,0 +1,234 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/config"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ErrLockHeld is returned when a lock stays held by someone else for the whole wait
+var ErrLockHeld = errors.New("lock already held")
+
+// Locker takes the distributed locks serializing changes to shared state such as
+// stock balances. Acquire takes the locks of all keys, in sorted order so callers
+// cannot deadlock, or none of them.
+type Locker interface {
+	Acquire(ctx context.Context, keys ...string) (*Lease, error)
+}
+
+// NewLocker builds the lock backend selected by the lock config. The Redis client
+// and database are only used by their backends.
+func NewLocker(cfg config.LockConfig, redisClient *RedisClient, db *gorm.DB, log *zap.Logger) (Locker, error) {
+	switch cfg.Backend {
+	case "redis":
+		return NewRedisLocker(redisClient, cfg, log), nil
+	case "postgres":
+		return NewPostgresLocker(db, cfg, log), nil
+	case "memory":
+		return NewMemoryLocker(cfg), nil
+	}
+	return nil, fmt.Errorf("unknown lock backend: %s", cfg.Backend)
+}
+
+// StockLockKey returns the lock key guarding one item's balance at one warehouse
+func StockLockKey(itemID, warehouseID uuid.UUID) string {
+	return fmt.Sprintf("lock:stock:%s:%s", itemID.String(), warehouseID.String())
+}
+
+// Lease holds the locks of a set of keys until Release. Each key has a fencing
+// token: every acquisition of a key gets a larger token than the one before, so a
+// resource that remembers the largest token it has seen can refuse writes from a
+// holder whose lease ran out and was taken over. Tokens are microsecond timestamps
+// (or one more than the key's last token), so they stay comparable when the
+// backend changes.
+type Lease struct {
+	tokens  map[string]int64
+	release func(ctx context.Context) error
+	lost    atomic.Bool
+	stop    chan struct{}
+	done    chan struct{}
+
+	// ttl and renewedAt (unix nanoseconds of the last successful renewal) bound how
+	// long a watched lease is trusted without the backend confirming it
+	ttl       time.Duration
+	renewedAt atomic.Int64
+}
+
+func newLease(tokens map[string]int64, release func(ctx context.Context) error) *Lease {
+	lease := &Lease{
+		tokens:  tokens,
+		release: release,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	return lease
+}
+
+// Keys returns the locked keys in sorted order
+func (l *Lease) Keys() []string {
+	keys := make([]string, 0, len(l.tokens))
+	for key := range l.tokens {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Token returns the fencing token of a locked key
+func (l *Lease) Token(key string) (int64, bool) {
+	token, ok := l.tokens[key]
+	return token, ok
+}
+
+// Check returns an error once the lease could not be renewed and its locks may be
+// held by someone else
+func (l *Lease) Check() error {
+	if l.lost.Load() || l.expired() {
+		return errors.New("lock lease was lost")
+	}
+	return nil
+}
+
+// expired reports whether a watched lease went a whole TTL without a successful
+// renewal, after which the backend may have let its locks expire
+func (l *Lease) expired() bool {
+	return l.ttl > 0 && time.Since(time.Unix(0, l.renewedAt.Load())) >= l.ttl
+}
+
+// Release stops renewing the lease and releases its locks
+func (l *Lease) Release(ctx context.Context) error {
+	select {
+	case <-l.stop:
+		return nil
+	default:
+		close(l.stop)
+	}
+	<-l.done
+	return l.release(ctx)
+}
+
+// watch renews the lease every third of ttl in the background until it is
+// released. acquired is when the first lock was requested, a lower bound for when
+// the backend started its TTL. A renewal that reports the locks gone marks the
+// lease lost; failed renewals are retried, since the lease may still be valid,
+// until a whole TTL has passed since the last successful one.
+func (l *Lease) watch(ttl time.Duration, acquired time.Time, renew func(ctx context.Context) (bool, error), log *zap.Logger) {
+	l.ttl = ttl
+	l.renewedAt.Store(acquired.UnixNano())
+	go l.renewLoop(ttl/3, renew, log)
+}
+
+func (l *Lease) renewLoop(interval time.Duration, renew func(ctx context.Context) (bool, error), log *zap.Logger) {
+	defer close(l.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
+
+		attempted := time.Now()
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		held, err := renew(ctx)
+		cancel()
+		if err != nil {
+			log.Warn("Failed to renew lock lease", zap.Strings("keys", l.Keys()), zap.Error(err))
+			if l.expired() {
+				l.lost.Store(true)
+				log.Warn("Lock lease expired without renewal", zap.Strings("keys", l.Keys()))
+				return
+			}
+			continue
+		}
+		if !held {
+			l.lost.Store(true)
+			log.Warn("Lock lease lost", zap.Strings("keys", l.Keys()))
+			return
+		}
+		l.renewedAt.Store(attempted.UnixNano())
+	}
+}
+
+// emptyLease returns a lease over no keys; there is nothing to renew or release
+func emptyLease() *Lease {
+	return newLease(map[string]int64{}, func(ctx context.Context) error {
+		return nil
+	}).unwatched()
+}
+
+// unwatched marks a lease that is never renewed
+func (l *Lease) unwatched() *Lease {
+	close(l.done)
+	return l
+}
+
+// sortedKeys returns the distinct keys in sorted order
+func sortedKeys(keys []string) []string {
+	seen := make(map[string]bool, len(keys))
+	sorted := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if !seen[key] {
+			seen[key] = true
+			sorted = append(sorted, key)
+		}
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// withLockWait bounds an acquisition by the configured wait; zero means one attempt
+func withLockWait(ctx context.Context, cfg config.LockConfig) (context.Context, context.CancelFunc) {
+	if cfg.Wait <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, cfg.Wait)
+}
+
+// retryLock calls try until it takes the lock of key, backing off between attempts,
+// until ctx is done. Errors from try are returned unless ctx ended meanwhile.
+func retryLock(ctx context.Context, cfg config.LockConfig, key string, try func() (bool, error)) error {
+	for attempt := 0; ; attempt++ {
+		taken, err := try()
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+		if err == nil && taken {
+			return nil
+		}
+		if cfg.Wait <= 0 {
+			return fmt.Errorf("%w for %s", ErrLockHeld, key)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w for %s", ErrLockHeld, key)
+		case <-time.After(lockBackoff(attempt, cfg.RetryMin, cfg.RetryMax)):
+		}
+	}
+}
+
+// lockBackoff returns how long to wait before retry attempt n of a lock: doubling
+// from min up to max, with jitter so waiting clients do not retry in step
+func lockBackoff(attempt int, min, max time.Duration) time.Duration {
+	wait := max
+	if attempt < 16 && min<<attempt < max {
+		wait = min << attempt
+	}
+	if wait <= 0 {
+		return 0
+	}
+	return wait/2 + rand.N(wait/2+1)
+}
@@ -0,0 +1,77 @@
+package infrastructure
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/senoagung27/warehousex/internal/config"
+)
+
+// MemoryLocker keeps locks in process memory. It only serializes callers in one
+// process, so it suits single-replica installs and development. Its leases never
+// expire.
+type MemoryLocker struct {
+	cfg config.LockConfig
+
+	mu        sync.Mutex
+	held      map[string]bool
+	lastToken map[string]int64
+}
+
+func NewMemoryLocker(cfg config.LockConfig) *MemoryLocker {
+	return &MemoryLocker{
+		cfg:       cfg,
+		held:      make(map[string]bool),
+		lastToken: make(map[string]int64),
+	}
+}
+
+func (l *MemoryLocker) Acquire(ctx context.Context, keys ...string) (*Lease, error) {
+	ctx, cancel := withLockWait(ctx, l.cfg)
+	defer cancel()
+
+	tokens := make(map[string]int64, len(keys))
+	for _, key := range sortedKeys(keys) {
+		err := retryLock(ctx, l.cfg, key, func() (bool, error) {
+			return l.take(key, tokens), nil
+		})
+		if err != nil {
+			l.release(tokens)
+			return nil, err
+		}
+	}
+
+	lease := newLease(tokens, func(ctx context.Context) error {
+		l.release(tokens)
+		return nil
+	})
+	return lease.unwatched(), nil
+}
+
+// take locks a free key and records its fencing token
+func (l *MemoryLocker) take(key string, tokens map[string]int64) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.held[key] {
+		return false
+	}
+	token := time.Now().UnixMicro()
+	if last := l.lastToken[key]; token <= last {
+		token = last + 1
+	}
+	l.held[key] = true
+	l.lastToken[key] = token
+	tokens[key] = token
+	return true
+}
+
+func (l *MemoryLocker) release(tokens map[string]int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for key := range tokens {
+		delete(l.held, key)
+	}
+}
@@ -0,0 +1,29 @@
+package infrastructure_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/senoagung27/warehousex/internal/config"
+	"github.com/senoagung27/warehousex/internal/infrastructure"
+	"github.com/senoagung27/warehousex/internal/infrastructure/lockertest"
+)
+
+// testLockConfig keeps waits short so the contention cases finish quickly
+func testLockConfig(backend string) config.LockConfig {
+	return config.LockConfig{
+		Backend:  backend,
+		TTL:      10 * time.Second,
+		Wait:     200 * time.Millisecond,
+		RetryMin: 5 * time.Millisecond,
+		RetryMax: 50 * time.Millisecond,
+	}
+}
+
+func TestMemoryLocker(t *testing.T) {
+	// Memory locks only exclude callers of the same instance, as one process would
+	locker := infrastructure.NewMemoryLocker(testLockConfig("memory"))
+	lockertest.Run(t, func(t *testing.T) infrastructure.Locker {
+		return locker
+	})
+}
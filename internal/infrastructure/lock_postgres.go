@@ -0,0 +1,92 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/senoagung27/warehousex/internal/config"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// PostgresLocker takes transaction-level advisory locks (pg_advisory_xact_lock) in
+// a transaction of its own that stays open until the lease is released; rolling it
+// back releases the locks. Each lease therefore holds one database connection,
+// and the pool must have room for it besides the connection doing the work.
+// Keys are hashed to the 64-bit advisory lock IDs.
+type PostgresLocker struct {
+	db  *gorm.DB
+	cfg config.LockConfig
+	log *zap.Logger
+}
+
+func NewPostgresLocker(db *gorm.DB, cfg config.LockConfig, log *zap.Logger) *PostgresLocker {
+	return &PostgresLocker{db: db, cfg: cfg, log: log}
+}
+
+// advisoryLockID maps a lock key to an advisory lock ID
+func advisoryLockID(key string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(key))
+	return int64(hash.Sum64())
+}
+
+func (l *PostgresLocker) Acquire(ctx context.Context, keys ...string) (*Lease, error) {
+	// Without keys there is nothing to lock; do not tie up a pooled connection
+	if len(keys) == 0 {
+		return emptyLease(), nil
+	}
+
+	acquired := time.Now()
+	ctx, cancel := withLockWait(ctx, l.cfg)
+	defer cancel()
+
+	// The lock transaction outlives the request context; cancelling a statement
+	// would abort it and drop the locks
+	tx := l.db.WithContext(context.Background()).Begin()
+	if tx.Error != nil {
+		return nil, fmt.Errorf("failed to begin lock transaction: %w", tx.Error)
+	}
+
+	tokens := make(map[string]int64, len(keys))
+	for _, key := range sortedKeys(keys) {
+		err := retryLock(ctx, l.cfg, key, func() (bool, error) {
+			var taken bool
+			if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", advisoryLockID(key)).Scan(&taken).Error; err != nil {
+				return false, fmt.Errorf("failed to acquire lock: %w", err)
+			}
+			return taken, nil
+		})
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		var token int64
+		if err := tx.Raw("SELECT (EXTRACT(EPOCH FROM clock_timestamp()) * 1000000)::bigint").Scan(&token).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to issue fencing token: %w", err)
+		}
+		tokens[key] = token
+	}
+
+	lease := newLease(tokens, func(ctx context.Context) error {
+		if err := tx.Rollback().Error; err != nil {
+			return fmt.Errorf("failed to release locks: %w", err)
+		}
+		return nil
+	})
+
+	// The locks live as long as the connection; a failed ping means it is gone
+	lease.watch(l.cfg.TTL, acquired, func(ctx context.Context) (bool, error) {
+		if err := tx.Exec("SELECT 1").Error; err != nil {
+			return false, nil
+		}
+		return true, nil
+	}, l.log)
+
+	l.log.Debug("Advisory locks acquired", zap.Strings("keys", lease.Keys()))
+	return lease, nil
+}
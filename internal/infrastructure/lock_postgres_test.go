@@ -0,0 +1,35 @@
+package infrastructure_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/senoagung27/warehousex/internal/infrastructure"
+	"github.com/senoagung27/warehousex/internal/infrastructure/lockertest"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestPostgresLocker runs against the database at DATABASE_URL
+func TestPostgresLocker(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get underlying sql.DB: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	lockertest.Run(t, func(t *testing.T) infrastructure.Locker {
+		return infrastructure.NewPostgresLocker(db, testLockConfig("postgres"), zap.NewNop())
+	})
+}
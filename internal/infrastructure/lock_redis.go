@@ -0,0 +1,128 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/senoagung27/warehousex/internal/config"
+	"go.uber.org/zap"
+)
+
+// acquireLockScript takes a free lock and issues its fencing token: the Redis time
+// in microseconds, or one more than the key's last token if that is larger, so
+// tokens keep increasing even if the counter is lost
+var acquireLockScript = redis.NewScript(`
+	if not redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+		return 0
+	end
+	local now = redis.call("TIME")
+	local token = tonumber(now[1]) * 1000000 + tonumber(now[2])
+	local last = tonumber(redis.call("GET", KEYS[2]) or "0")
+	if token <= last then
+		token = last + 1
+	end
+	redis.call("SET", KEYS[2], token)
+	return token
+`)
+
+// renewLockScript extends a lock only while the caller still holds it
+var renewLockScript = redis.NewScript(`
+	if redis.call("GET", KEYS[1]) == ARGV[1] then
+		return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	end
+	return 0
+`)
+
+// releaseLockScript deletes a lock only while the caller still holds it
+var releaseLockScript = redis.NewScript(`
+	if redis.call("GET", KEYS[1]) == ARGV[1] then
+		return redis.call("DEL", KEYS[1])
+	end
+	return 0
+`)
+
+// RedisLocker keeps each lock as a Redis key set with NX and a TTL; the lease is
+// renewed every third of the TTL while held
+type RedisLocker struct {
+	client *redis.Client
+	cfg    config.LockConfig
+	log    *zap.Logger
+}
+
+func NewRedisLocker(redisClient *RedisClient, cfg config.LockConfig, log *zap.Logger) *RedisLocker {
+	return &RedisLocker{client: redisClient.Client, cfg: cfg, log: log}
+}
+
+func (l *RedisLocker) Acquire(ctx context.Context, keys ...string) (*Lease, error) {
+	acquired := time.Now()
+	ctx, cancel := withLockWait(ctx, l.cfg)
+	defer cancel()
+
+	values := make(map[string]string, len(keys))
+	tokens := make(map[string]int64, len(keys))
+
+	for _, key := range sortedKeys(keys) {
+		value := uuid.New().String()
+		err := retryLock(ctx, l.cfg, key, func() (bool, error) {
+			token, err := acquireLockScript.Run(ctx, l.client, []string{key, "fence:" + key},
+				value, l.cfg.TTL.Milliseconds()).Int64()
+			if err != nil {
+				return false, fmt.Errorf("failed to acquire lock: %w", err)
+			}
+			tokens[key] = token
+			return token > 0, nil
+		})
+		if err != nil {
+			_ = l.release(context.Background(), values)
+			return nil, err
+		}
+		values[key] = value
+	}
+
+	lease := newLease(tokens, func(ctx context.Context) error {
+		return l.release(ctx, values)
+	})
+	lease.watch(l.cfg.TTL, acquired, func(ctx context.Context) (bool, error) {
+		return l.renew(ctx, values)
+	}, l.log)
+
+	l.log.Debug("Locks acquired", zap.Strings("keys", lease.Keys()))
+	return lease, nil
+}
+
+// renew extends every lock of a lease; it reports false if any was lost
+func (l *RedisLocker) renew(ctx context.Context, values map[string]string) (bool, error) {
+	for key, value := range values {
+		renewed, err := renewLockScript.Run(ctx, l.client, []string{key}, value, l.cfg.TTL.Milliseconds()).Int64()
+		if err != nil {
+			return false, fmt.Errorf("failed to renew lock: %w", err)
+		}
+		if renewed == 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// release deletes the locks still held by the caller
+func (l *RedisLocker) release(ctx context.Context, values map[string]string) error {
+	var firstErr error
+	for key, value := range values {
+		result, err := releaseLockScript.Run(ctx, l.client, []string{key}, value).Int64()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to release lock: %w", err)
+			}
+			continue
+		}
+		if result == 0 {
+			l.log.Warn("Lock was not held or expired",
+				zap.String("key", key),
+			)
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,31 @@
+package infrastructure_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/senoagung27/warehousex/internal/infrastructure"
+	"github.com/senoagung27/warehousex/internal/infrastructure/lockertest"
+	"go.uber.org/zap"
+)
+
+// TestRedisLocker runs against the Redis at REDIS_ADDR (host:port)
+func TestRedisLocker(t *testing.T) {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_ADDR not set")
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	t.Cleanup(func() { client.Close() })
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Fatalf("failed to connect to Redis: %v", err)
+	}
+
+	redisClient := &infrastructure.RedisClient{Client: client}
+	lockertest.Run(t, func(t *testing.T) infrastructure.Locker {
+		return infrastructure.NewRedisLocker(redisClient, testLockConfig("redis"), zap.NewNop())
+	})
+}
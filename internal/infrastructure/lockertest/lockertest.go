@@ -0,0 +1,217 @@
+// Package lockertest is the conformance suite every infrastructure.Locker must
+// pass. Call Run from a backend's test with a factory for fresh lockers that share
+// one backend, e.g. two RedisLockers on the same Redis:
+//
+//	func TestRedisLocker(t *testing.T) {
+//		lockertest.Run(t, func(t *testing.T) infrastructure.Locker {
+//			return infrastructure.NewRedisLocker(client, cfg, zap.NewNop())
+//		})
+//	}
+//
+// The factory should configure a short wait (about 200ms) so the contention cases
+// finish quickly; keys are random, so runs do not interfere.
+package lockertest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/infrastructure"
+)
+
+// Factory returns a locker on the backend under test. Lockers from one factory
+// must exclude each other, as replicas sharing the backend would.
+type Factory func(t *testing.T) infrastructure.Locker
+
+// Run runs the conformance suite
+func Run(t *testing.T, newLocker Factory) {
+	t.Run("AcquireRelease", func(t *testing.T) { testAcquireRelease(t, newLocker) })
+	t.Run("MutualExclusion", func(t *testing.T) { testMutualExclusion(t, newLocker) })
+	t.Run("WaitsForRelease", func(t *testing.T) { testWaitsForRelease(t, newLocker) })
+	t.Run("ContextCancel", func(t *testing.T) { testContextCancel(t, newLocker) })
+	t.Run("AllOrNothing", func(t *testing.T) { testAllOrNothing(t, newLocker) })
+	t.Run("FencingTokensIncrease", func(t *testing.T) { testFencingTokensIncrease(t, newLocker) })
+	t.Run("SerializesCriticalSections", func(t *testing.T) { testSerializesCriticalSections(t, newLocker) })
+}
+
+func key(t *testing.T) string {
+	return "lockertest:" + t.Name() + ":" + uuid.New().String()
+}
+
+func acquire(t *testing.T, locker infrastructure.Locker, keys ...string) *infrastructure.Lease {
+	t.Helper()
+	lease, err := locker.Acquire(context.Background(), keys...)
+	if err != nil {
+		t.Fatalf("acquire %v: %v", keys, err)
+	}
+	return lease
+}
+
+func release(t *testing.T, lease *infrastructure.Lease) {
+	t.Helper()
+	if err := lease.Release(context.Background()); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+}
+
+func testAcquireRelease(t *testing.T, newLocker Factory) {
+	locker := newLocker(t)
+	k := key(t)
+
+	lease := acquire(t, locker, k, k)
+	if keys := lease.Keys(); len(keys) != 1 || keys[0] != k {
+		t.Fatalf("lease keys = %v, want [%s]", keys, k)
+	}
+	if token, ok := lease.Token(k); !ok || token <= 0 {
+		t.Fatalf("token = %d, %v; want a positive token", token, ok)
+	}
+	if err := lease.Check(); err != nil {
+		t.Fatalf("fresh lease: %v", err)
+	}
+	release(t, lease)
+
+	// Releasing twice is harmless
+	if err := lease.Release(context.Background()); err != nil {
+		t.Fatalf("second release: %v", err)
+	}
+
+	release(t, acquire(t, locker, k))
+}
+
+func testMutualExclusion(t *testing.T, newLocker Factory) {
+	first, second := newLocker(t), newLocker(t)
+	k := key(t)
+
+	lease := acquire(t, first, k)
+	defer release(t, lease)
+
+	if other, err := second.Acquire(context.Background(), k); err == nil {
+		_ = other.Release(context.Background())
+		t.Fatal("a held lock was acquired again")
+	} else if !errors.Is(err, infrastructure.ErrLockHeld) {
+		t.Fatalf("error = %v, want ErrLockHeld", err)
+	}
+}
+
+func testWaitsForRelease(t *testing.T, newLocker Factory) {
+	first, second := newLocker(t), newLocker(t)
+	k := key(t)
+
+	lease := acquire(t, first, k)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = lease.Release(context.Background())
+	}()
+
+	release(t, acquire(t, second, k))
+}
+
+func testContextCancel(t *testing.T, newLocker Factory) {
+	first, second := newLocker(t), newLocker(t)
+	k := key(t)
+
+	lease := acquire(t, first, k)
+	defer release(t, lease)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if other, err := second.Acquire(ctx, k); err == nil {
+		_ = other.Release(context.Background())
+		t.Fatal("a held lock was acquired again")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("acquire ignored the cancelled context for %v", elapsed)
+	}
+}
+
+func testAllOrNothing(t *testing.T, newLocker Factory) {
+	first, second := newLocker(t), newLocker(t)
+	free, taken := key(t), key(t)
+
+	lease := acquire(t, first, taken)
+	if other, err := second.Acquire(context.Background(), free, taken); err == nil {
+		_ = other.Release(context.Background())
+		t.Fatal("a held lock was acquired again")
+	}
+	release(t, lease)
+
+	// The failed acquisition must not keep the free key
+	release(t, acquire(t, first, free))
+}
+
+func testFencingTokensIncrease(t *testing.T, newLocker Factory) {
+	first, second := newLocker(t), newLocker(t)
+	k := key(t)
+
+	var last int64
+	for i, locker := range []infrastructure.Locker{first, second, first, second} {
+		lease := acquire(t, locker, k)
+		token, _ := lease.Token(k)
+		if token <= last {
+			t.Fatalf("acquisition %d: token %d not above previous %d", i, token, last)
+		}
+		last = token
+		release(t, lease)
+	}
+}
+
+func testSerializesCriticalSections(t *testing.T, newLocker Factory) {
+	const workers, rounds = 4, 10
+	k := key(t)
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		inside  int
+		overlap bool
+		done    int
+	)
+	for w := 0; w < workers; w++ {
+		locker := newLocker(t)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := 0; r < rounds; r++ {
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				lease, err := locker.Acquire(ctx, k)
+				cancel()
+				if errors.Is(err, infrastructure.ErrLockHeld) {
+					r--
+					continue
+				}
+				if err != nil {
+					t.Errorf("acquire: %v", err)
+					return
+				}
+
+				mu.Lock()
+				inside++
+				overlap = overlap || inside > 1
+				mu.Unlock()
+
+				time.Sleep(time.Millisecond)
+
+				mu.Lock()
+				inside--
+				done++
+				mu.Unlock()
+
+				_ = lease.Release(context.Background())
+			}
+		}()
+	}
+	wg.Wait()
+
+	if overlap {
+		t.Fatal("two holders were inside the critical section at once")
+	}
+	if done != workers*rounds {
+		t.Fatalf("completed %d critical sections, want %d", done, workers*rounds)
+	}
+}
@@ -0,0 +1,81 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// LoginAccountSubject identifies an account in the login throttle; it is keyed by
+// email so unknown accounts are throttled like real ones
+func LoginAccountSubject(email string) string {
+	return "account:" + strings.ToLower(email)
+}
+
+// LoginIPSubject identifies a client IP in the login throttle
+func LoginIPSubject(ip string) string {
+	return "ip:" + ip
+}
+
+func loginFailuresKey(subject string) string {
+	return "login_failures:" + subject
+}
+
+func loginBlockedKey(subject string) string {
+	return "login_blocked:" + subject
+}
+
+// RecordLoginFailure counts a failed login for the subject and returns the
+// failures within window; the count is forgotten window after the last failure
+func (r *RedisClient) RecordLoginFailure(ctx context.Context, subject string, window time.Duration) (int64, error) {
+	key := loginFailuresKey(subject)
+
+	pipe := r.Client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.PExpire(ctx, key, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to record login failure: %w", err)
+	}
+	return incr.Val(), nil
+}
+
+// BlockLogin refuses logins for the subject for d
+func (r *RedisClient) BlockLogin(ctx context.Context, subject string, d time.Duration) error {
+	if err := r.Client.Set(ctx, loginBlockedKey(subject), 1, d).Err(); err != nil {
+		return fmt.Errorf("failed to block login: %w", err)
+	}
+	return nil
+}
+
+// LoginBlockedFor returns how long the longest block on any of the subjects has
+// left, or zero if none is blocked
+func (r *RedisClient) LoginBlockedFor(ctx context.Context, subjects ...string) (time.Duration, error) {
+	pipe := r.Client.Pipeline()
+	ttls := make([]*redis.DurationCmd, len(subjects))
+	for i, subject := range subjects {
+		ttls[i] = pipe.PTTL(ctx, loginBlockedKey(subject))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to check login block: %w", err)
+	}
+
+	var longest time.Duration
+	for _, ttl := range ttls {
+		// Missing keys report a negative TTL
+		if ttl.Val() > longest {
+			longest = ttl.Val()
+		}
+	}
+	return longest, nil
+}
+
+// ClearLoginFailures forgets the failures and block of the subject
+func (r *RedisClient) ClearLoginFailures(ctx context.Context, subject string) error {
+	if err := r.Client.Del(ctx, loginFailuresKey(subject), loginBlockedKey(subject)).Err(); err != nil {
+		return fmt.Errorf("failed to clear login failures: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package infrastructure
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Notification is a message for a set of users
+type Notification struct {
+	UserIDs []uuid.UUID
+	Subject string
+	Body    string
+}
+
+// Notifier delivers notifications to users
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// LogNotifier writes notifications to the application log; it stands in until a
+// mail or chat channel is configured
+type LogNotifier struct {
+	log *zap.Logger
+}
+
+func NewLogNotifier(log *zap.Logger) *LogNotifier {
+	return &LogNotifier{log: log}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, notification Notification) error {
+	recipients := make([]string, len(notification.UserIDs))
+	for i, id := range notification.UserIDs {
+		recipients[i] = id.String()
+	}
+
+	n.log.Info("Notification",
+		zap.Strings("recipients", recipients),
+		zap.String("subject", notification.Subject),
+		zap.String("body", notification.Body),
+	)
+	return nil
+}
@@ -0,0 +1,32 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func revokedTokenKey(jti string) string {
+	return "revoked_token:" + jti
+}
+
+// RevokeToken puts an access token's jti on the revocation list until ttl, the
+// token's remaining lifetime, passes
+func (r *RedisClient) RevokeToken(ctx context.Context, jti string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	if err := r.Client.Set(ctx, revokedTokenKey(jti), 1, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	return nil
+}
+
+// IsTokenRevoked checks if an access token's jti is on the revocation list
+func (r *RedisClient) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	n, err := r.Client.Exists(ctx, revokedTokenKey(jti)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+	return n > 0, nil
+}
@@ -3,14 +3,19 @@ package middleware
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/domain/repository"
+	"github.com/senoagung27/warehousex/internal/infrastructure"
 )
 
-// JWTAuth validates JWT token and extracts claims into context
-func JWTAuth(jwtSecret string) gin.HandlerFunc {
+// JWTAuth validates JWT token and extracts claims into context. Tokens whose jti is
+// on the revocation list are refused, and the user is looked up on every request,
+// so logout, deactivation and role changes apply to tokens already issued.
+func JWTAuth(keys *infrastructure.KeySet, userRepo repository.UserRepository, redisClient *infrastructure.RedisClient) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -29,12 +34,7 @@ func JWTAuth(jwtSecret string) gin.HandlerFunc {
 
 		tokenString := parts[1]
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return []byte(jwtSecret), nil
-		})
+		token, err := jwt.Parse(tokenString, keys.Keyfunc, jwt.WithValidMethods(keys.Methods()))
 
 		if err != nil || !token.Valid {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
@@ -49,6 +49,26 @@ func JWTAuth(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
+		jti, _ := claims["jti"].(string)
+		expiresAt, err := claims.GetExpirationTime()
+		if jti == "" || err != nil || expiresAt == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token claims"})
+			c.Abort()
+			return
+		}
+
+		revoked, err := redisClient.IsTokenRevoked(c.Request.Context(), jti)
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "token revocation check unavailable"})
+			c.Abort()
+			return
+		}
+		if revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "token revoked"})
+			c.Abort()
+			return
+		}
+
 		userIDStr, _ := claims["user_id"].(string)
 		userID, err := uuid.Parse(userIDStr)
 		if err != nil {
@@ -57,10 +77,22 @@ func JWTAuth(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
-		role, _ := claims["role"].(string)
+		user, err := userRepo.FindByID(userID)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+			c.Abort()
+			return
+		}
+		if err := user.CheckAccount(); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
 
 		c.Set("user_id", userID)
-		c.Set("user_role", role)
+		c.Set("user_role", user.Role)
+		c.Set("token_id", jti)
+		c.Set("token_expires_at", expiresAt.Time)
 		c.Next()
 	}
 }
@@ -76,3 +108,15 @@ func GetUserRole(c *gin.Context) string {
 	role, _ := c.Get("user_role")
 	return role.(string)
 }
+
+// GetTokenID extracts the access token's jti from gin context
+func GetTokenID(c *gin.Context) string {
+	jti, _ := c.Get("token_id")
+	return jti.(string)
+}
+
+// GetTokenExpiry extracts the access token's expiry from gin context
+func GetTokenExpiry(c *gin.Context) time.Time {
+	exp, _ := c.Get("token_expires_at")
+	return exp.(time.Time)
+}
@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/senoagung27/warehousex/internal/infrastructure"
+)
+
+const (
+	// IdempotencyHeader is the request header carrying the client's key
+	IdempotencyHeader = "Idempotency-Key"
+	// maxIdempotencyKeyLength bounds the keys clients may send
+	maxIdempotencyKeyLength = 255
+	// idempotencyWait is how long a duplicate waits for the first request to finish
+	idempotencyWait = 10 * time.Second
+	// idempotencyPoll is how often a waiting duplicate retries the lock
+	idempotencyPoll = 100 * time.Millisecond
+)
+
+// responseRecorder copies the response body while it is written to the client
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency replays the stored response when a request is repeated with the same
+// Idempotency-Key. Keys are scoped to the user and bound to the method, path and
+// body of the first request; reusing one for a different request is refused with
+// 422. Concurrent requests with one key are serialized with a lock, so the
+// duplicate waits and receives the first request's response. Server errors and
+// conflicts are not stored, so they can be retried. Requests without the header
+// pass through.
+func Idempotency(redisClient *infrastructure.RedisClient, locker infrastructure.Locker, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key must be at most 255 characters"})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		hash.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + "\n"))
+		hash.Write(body)
+		fingerprint := hex.EncodeToString(hash.Sum(nil))
+
+		ctx := c.Request.Context()
+		storeKey := infrastructure.IdempotencyKey(GetUserID(c).String(), key)
+
+		lockKey := "lock:" + storeKey
+		lease, err := waitForLock(ctx, locker, lockKey)
+		if err != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "a request with this Idempotency-Key is still in progress"})
+			c.Abort()
+			return
+		}
+		defer func() {
+			_ = lease.Release(context.Background())
+		}()
+
+		stored, err := redisClient.GetIdempotentResponse(ctx, storeKey)
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "idempotency store unavailable"})
+			c.Abort()
+			return
+		}
+		if stored != nil {
+			if stored.Fingerprint != fingerprint {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was already used for a different request"})
+				c.Abort()
+				return
+			}
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(stored.Status, stored.ContentType, stored.Body)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError || status == http.StatusConflict {
+			return
+		}
+		_ = redisClient.SaveIdempotentResponse(context.Background(), storeKey, &infrastructure.IdempotentResponse{
+			Fingerprint: fingerprint,
+			Status:      status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		}, ttl)
+	}
+}
+
+// waitForLock retries a lock until it is free or idempotencyWait passes; a
+// duplicate may wait longer than the lock's own bounded wait
+func waitForLock(ctx context.Context, locker infrastructure.Locker, lockKey string) (*infrastructure.Lease, error) {
+	ctx, cancel := context.WithTimeout(ctx, idempotencyWait)
+	defer cancel()
+
+	for {
+		lease, err := locker.Acquire(ctx, lockKey)
+		if err == nil {
+			return lease, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(idempotencyPoll):
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApprovalPolicy requires a chain of approvals for requests it matches. A policy
+// matches on request type and item category (empty matches any) when the lines in
+// that category exceed QuantityOver units and, if set, ValueOver in total value.
+// Steps lists the role required at each step, in signing order.
+type ApprovalPolicy struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Name         string    `gorm:"size:255;not null" json:"name"`
+	RequestType  string    `gorm:"size:20" json:"request_type,omitempty"`
+	Category     string    `gorm:"size:100" json:"category,omitempty"`
+	QuantityOver int       `gorm:"not null;default:0" json:"quantity_over"`
+	ValueOver    float64   `gorm:"type:numeric(14,2);not null;default:0" json:"value_over"`
+	Active       bool      `gorm:"not null;default:true" json:"active"`
+	CreatedBy    uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relations (for preloading)
+	Steps []ApprovalPolicyStep `gorm:"foreignKey:PolicyID" json:"steps,omitempty"`
+}
+
+func (ApprovalPolicy) TableName() string {
+	return "approval_policies"
+}
+
+// Matches checks the policy against a request of the given type whose lines in the
+// policy's category total quantity units worth value
+func (p ApprovalPolicy) Matches(reqType string, quantity int, value float64) bool {
+	if p.RequestType != "" && p.RequestType != reqType {
+		return false
+	}
+	if quantity == 0 || quantity <= p.QuantityOver {
+		return false
+	}
+	return p.ValueOver == 0 || value > p.ValueOver
+}
+
+// ApprovalPolicyStep is one step of a policy's approval chain
+type ApprovalPolicyStep struct {
+	ID       uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	PolicyID uuid.UUID `gorm:"type:uuid;not null" json:"policy_id"`
+	StepNo   int       `gorm:"not null" json:"step_no"`
+	Role     string    `gorm:"size:50;not null" json:"role"`
+}
+
+func (ApprovalPolicyStep) TableName() string {
+	return "approval_policy_steps"
+}
+
+// RequestApproval is one step of a request's approval chain, fixed when the
+// request is created. ApprovedBy and ApprovedAt are set once the step is signed;
+// OnBehalfOf is the original approver when a delegate signed it.
+type RequestApproval struct {
+	ID         uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RequestID  uuid.UUID  `gorm:"type:uuid;not null" json:"request_id"`
+	StepNo     int        `gorm:"not null" json:"step_no"`
+	Role       string     `gorm:"size:50;not null" json:"role"`
+	PolicyID   *uuid.UUID `gorm:"type:uuid" json:"policy_id,omitempty"`
+	ApprovedBy *uuid.UUID `gorm:"type:uuid" json:"approved_by,omitempty"`
+	OnBehalfOf *uuid.UUID `gorm:"type:uuid" json:"on_behalf_of,omitempty"`
+	ApprovedAt *time.Time `json:"approved_at,omitempty"`
+
+	// Relations (for preloading)
+	Approver *User `gorm:"foreignKey:ApprovedBy" json:"approver,omitempty"`
+}
+
+func (RequestApproval) TableName() string {
+	return "request_approvals"
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RequestAttachment is a file uploaded to a request, such as a delivery note or a
+// photo of damaged goods. The content lives in the blob store under StorageKey;
+// Checksum is its hex SHA-256.
+type RequestAttachment struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RequestID   uuid.UUID `gorm:"type:uuid;not null" json:"request_id"`
+	FileName    string    `gorm:"size:255;not null" json:"file_name"`
+	ContentType string    `gorm:"size:100;not null" json:"content_type"`
+	Size        int64     `gorm:"not null" json:"size"`
+	Checksum    string    `gorm:"size:64;not null" json:"checksum"`
+	StorageKey  string    `gorm:"size:255;not null" json:"-"`
+	UploadedBy  uuid.UUID `gorm:"type:uuid;not null" json:"uploaded_by"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relations (for preloading)
+	Uploader *User `gorm:"foreignKey:UploadedBy" json:"uploader,omitempty"`
+}
+
+func (RequestAttachment) TableName() string {
+	return "request_attachments"
+}
+
+// DownloadPath is the API path serving the attachment's content
+func (a RequestAttachment) DownloadPath() string {
+	return fmt.Sprintf("/api/v1/requests/%s/attachments/%s", a.RequestID, a.ID)
+}
+
+// MarshalJSON adds the download path to the attachment
+func (a RequestAttachment) MarshalJSON() ([]byte, error) {
+	type attachment RequestAttachment
+	return json.Marshal(struct {
+		attachment
+		DownloadURL string `json:"download_url"`
+	}{attachment(a), a.DownloadPath()})
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RequestComment is one message in a request's comment thread. Role is the
+// author's role when they wrote it.
+type RequestComment struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RequestID uuid.UUID `gorm:"type:uuid;not null" json:"request_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
+	Role      string    `gorm:"size:50;not null" json:"role"`
+	Body      string    `gorm:"type:text;not null" json:"body"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relations (for preloading)
+	Author *User `gorm:"foreignKey:UserID" json:"author,omitempty"`
+}
+
+func (RequestComment) TableName() string {
+	return "request_comments"
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApprovalDelegation lets a delegate sign approvals with the delegator's authority
+// between StartsAt and EndsAt, for the listed request types or all types when
+// RequestTypes is empty. A revoked delegation no longer applies.
+type ApprovalDelegation struct {
+	ID           uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	DelegatorID  uuid.UUID  `gorm:"type:uuid;not null" json:"delegator_id"`
+	DelegateID   uuid.UUID  `gorm:"type:uuid;not null" json:"delegate_id"`
+	RequestTypes StringList `gorm:"type:jsonb;not null;default:'[]'" json:"request_types"`
+	StartsAt     time.Time  `gorm:"not null" json:"starts_at"`
+	EndsAt       time.Time  `gorm:"not null" json:"ends_at"`
+	Reason       string     `gorm:"type:text" json:"reason,omitempty"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relations (for preloading)
+	Delegator *User `gorm:"foreignKey:DelegatorID" json:"delegator,omitempty"`
+	Delegate  *User `gorm:"foreignKey:DelegateID" json:"delegate,omitempty"`
+}
+
+func (ApprovalDelegation) TableName() string {
+	return "approval_delegations"
+}
+
+// Covers checks if the delegation applies to a request of the given type at the given time
+func (d ApprovalDelegation) Covers(reqType string, at time.Time) bool {
+	if d.RevokedAt != nil || at.Before(d.StartsAt) || !at.Before(d.EndsAt) {
+		return false
+	}
+	if len(d.RequestTypes) == 0 {
+		return true
+	}
+	for _, t := range d.RequestTypes {
+		if t == reqType {
+			return true
+		}
+	}
+	return false
+}
+
+// StringList is a list of strings stored as a JSONB array
+type StringList []string
+
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal([]string(l))
+	return string(b), err
+}
+
+func (l *StringList) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	}
+	return errors.New("unsupported type for StringList")
+}
@@ -4,19 +4,104 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// Inventory is a stock item. Serialized items are tracked unit by unit and
+// need serial numbers on every request. Category and UnitValue feed the
+// approval policies that decide how many approvals a request needs.
 type Inventory struct {
-	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	ItemName  string    `gorm:"size:255;not null" json:"item_name"`
-	SKU       string    `gorm:"size:100;uniqueIndex" json:"sku"`
-	Quantity  int       `gorm:"not null;default:0" json:"quantity"`
-	Unit      string    `gorm:"size:50;not null;default:'pcs'" json:"unit"`
-	Version   int       `gorm:"not null;default:1" json:"version"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ItemName   string    `gorm:"size:255;not null" json:"item_name"`
+	SKU        string    `gorm:"size:100;uniqueIndex" json:"sku"`
+	Unit       string    `gorm:"size:50;not null;default:'pcs'" json:"unit"`
+	Category   string    `gorm:"size:100" json:"category,omitempty"`
+	UnitValue  float64   `gorm:"type:numeric(14,2);not null;default:0" json:"unit_value"`
+	Serialized bool      `gorm:"not null;default:false" json:"serialized"`
+	Version    int       `gorm:"not null;default:1" json:"version"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Quantity is the total on hand across all warehouses; Reserved the part held by
+	// pending requests and Available the rest (all derived from Stocks)
+	Quantity  int `gorm:"-" json:"quantity"`
+	Reserved  int `gorm:"-" json:"reserved"`
+	Available int `gorm:"-" json:"available"`
+
+	// Relations (for preloading)
+	Stocks []InventoryStock `gorm:"foreignKey:ItemID" json:"stocks,omitempty"`
+	Bins   []BinStock       `gorm:"foreignKey:ItemID" json:"bins,omitempty"`
+	Lots   []Lot            `gorm:"foreignKey:ItemID" json:"lots,omitempty"`
 }
 
 func (Inventory) TableName() string {
 	return "inventory"
 }
+
+// AfterFind derives the totals from the preloaded per-warehouse balances
+func (i *Inventory) AfterFind(tx *gorm.DB) error {
+	i.SumStocks()
+	return nil
+}
+
+// SumStocks recomputes the totals from Stocks
+func (i *Inventory) SumStocks() {
+	i.Quantity, i.Reserved, i.Available = 0, 0, 0
+	for _, stock := range i.Stocks {
+		i.Quantity += stock.Quantity
+		i.Reserved += stock.Reserved
+		i.Available += stock.Quantity - stock.Reserved
+	}
+}
+
+// InventoryStock is the stock balance of one item at one warehouse. Quantity is
+// on hand; Reserved is held by pending outbound and transfer requests and is
+// never more than Quantity.
+// FenceToken is the fencing token of the last lock holder that wrote the balance.
+type InventoryStock struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ItemID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_inventory_stocks_item_warehouse" json:"item_id"`
+	WarehouseID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_inventory_stocks_item_warehouse" json:"warehouse_id"`
+	Quantity    int       `gorm:"not null;default:0" json:"quantity"`
+	Reserved    int       `gorm:"not null;default:0" json:"reserved"`
+	Available   int       `gorm:"-" json:"available"`
+	Version     int       `gorm:"not null;default:1" json:"version"`
+	FenceToken  int64     `gorm:"not null;default:0" json:"-"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relations (for preloading)
+	Warehouse *Warehouse      `gorm:"foreignKey:WarehouseID" json:"warehouse,omitempty"`
+	Movements []StockMovement `gorm:"foreignKey:StockID" json:"movements,omitempty"`
+}
+
+func (InventoryStock) TableName() string {
+	return "inventory_stocks"
+}
+
+// AfterFind derives the quantity free for new requests
+func (s *InventoryStock) AfterFind(tx *gorm.DB) error {
+	s.Available = s.Quantity - s.Reserved
+	return nil
+}
+
+// BinStock is the part of a warehouse balance stored in one bin location.
+// The sum over a warehouse's bins never exceeds its InventoryStock quantity;
+// the remainder is received but not yet put away.
+type BinStock struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ItemID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_bin_stocks_item_location" json:"item_id"`
+	WarehouseID uuid.UUID `gorm:"type:uuid;not null" json:"warehouse_id"`
+	LocationID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_bin_stocks_item_location" json:"location_id"`
+	Quantity    int       `gorm:"not null;default:0" json:"quantity"`
+	Version     int       `gorm:"not null;default:1" json:"version"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relations (for preloading)
+	Location *Location `gorm:"foreignKey:LocationID" json:"location,omitempty"`
+}
+
+func (BinStock) TableName() string {
+	return "bin_stocks"
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invitation lets the holder of its token create one account with the given email
+// and role before ExpiresAt. Only the SHA-256 hash of the token is stored.
+type Invitation struct {
+	ID         uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Email      string     `gorm:"size:255;not null" json:"email"`
+	Role       string     `gorm:"size:50;not null" json:"role"`
+	TokenHash  string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	CreatedBy  uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	AcceptedBy *uuid.UUID `gorm:"type:uuid" json:"accepted_by,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (Invitation) TableName() string {
+	return "user_invitations"
+}
+
+// Usable checks if the invitation can still be accepted at the given time
+func (i Invitation) Usable(at time.Time) bool {
+	return i.AcceptedAt == nil && i.RevokedAt == nil && at.Before(i.ExpiresAt)
+}
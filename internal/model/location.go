@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Location types, nested zone → aisle → rack → bin
+const (
+	LocationTypeZone  = "ZONE"
+	LocationTypeAisle = "AISLE"
+	LocationTypeRack  = "RACK"
+	LocationTypeBin   = "BIN"
+)
+
+type Location struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	WarehouseID uuid.UUID  `gorm:"type:uuid;not null" json:"warehouse_id"`
+	ParentID    *uuid.UUID `gorm:"type:uuid" json:"parent_id,omitempty"`
+	Type        string     `gorm:"size:20;not null" json:"type"`
+	Code        string     `gorm:"size:50;not null" json:"code"`
+	Path        string     `gorm:"size:255;not null" json:"path"`
+	Name        string     `gorm:"size:255" json:"name,omitempty"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (Location) TableName() string {
+	return "locations"
+}
+
+// ParentLocationType returns the type a location of the given type must be nested under.
+// Zones sit directly under the warehouse and return an empty string.
+func ParentLocationType(locationType string) (string, bool) {
+	parents := map[string]string{
+		LocationTypeZone:  "",
+		LocationTypeAisle: LocationTypeZone,
+		LocationTypeRack:  LocationTypeAisle,
+		LocationTypeBin:   LocationTypeRack,
+	}
+	parent, ok := parents[locationType]
+	return parent, ok
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Lot is a received batch of an item at one warehouse. Lots are consumed
+// first-expired-first-out; lots without an expiry date are picked last and expired
+// lots are not picked.
+type Lot struct {
+	ID             uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ItemID         uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_lots_item_warehouse_number" json:"item_id"`
+	WarehouseID    uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_lots_item_warehouse_number" json:"warehouse_id"`
+	LotNumber      string     `gorm:"size:100;not null;uniqueIndex:idx_lots_item_warehouse_number" json:"lot_number"`
+	ManufacturedAt *time.Time `json:"manufactured_at,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	Quantity       int        `gorm:"not null;default:0" json:"quantity"`
+	Version        int        `gorm:"not null;default:1" json:"version"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relations (for preloading)
+	Item      *Inventory `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+	Warehouse *Warehouse `gorm:"foreignKey:WarehouseID" json:"warehouse,omitempty"`
+}
+
+func (Lot) TableName() string {
+	return "lots"
+}
+
+// RequestLot records how much of a lot an outbound or transfer request line consumed.
+// Outbound allocations also record the shipment that picked them.
+type RequestLot struct {
+	ID         uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RequestID  uuid.UUID  `gorm:"type:uuid;not null" json:"request_id"`
+	LineID     uuid.UUID  `gorm:"type:uuid;not null" json:"line_id"`
+	ShipmentID *uuid.UUID `gorm:"type:uuid" json:"shipment_id,omitempty"`
+	LotID      uuid.UUID  `gorm:"type:uuid;not null" json:"lot_id"`
+	Quantity   int        `gorm:"not null" json:"quantity"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relations (for preloading)
+	Lot *Lot `gorm:"foreignKey:LotID" json:"lot,omitempty"`
+}
+
+func (RequestLot) TableName() string {
+	return "request_lots"
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken is one link in a chain of refresh tokens that starts at login. Each
+// refresh marks the presented token used and issues the next one in the same
+// family, so a used token showing up again means it was stolen and the whole
+// family is revoked. AccessJTI is the access token issued alongside it, revoked
+// with the family. Only the SHA-256 hash of the token is stored.
+type RefreshToken struct {
+	ID              uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID          uuid.UUID  `gorm:"type:uuid;not null" json:"user_id"`
+	FamilyID        uuid.UUID  `gorm:"type:uuid;not null" json:"family_id"`
+	TokenHash       string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	AccessJTI       string     `gorm:"column:access_jti;size:64;not null" json:"-"`
+	AccessExpiresAt time.Time  `gorm:"not null" json:"-"`
+	ExpiresAt       time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt          *time.Time `json:"used_at,omitempty"`
+	RevokedAt       *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
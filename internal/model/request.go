@@ -10,43 +10,117 @@ import (
 const (
 	RequestTypeInbound  = "INBOUND"
 	RequestTypeOutbound = "OUTBOUND"
+	RequestTypeTransfer = "TRANSFER"
 )
 
 // Request statuses (state machine)
 const (
-	StatusPending   = "PENDING"
-	StatusApproved  = "APPROVED"
-	StatusRejected  = "REJECTED"
-	StatusCompleted = "COMPLETED"
+	StatusPending            = "PENDING"
+	StatusApproved           = "APPROVED"
+	StatusPartiallyFulfilled = "PARTIALLY_FULFILLED"
+	StatusRejected           = "REJECTED"
+	StatusCompleted          = "COMPLETED"
+	StatusCancelled          = "CANCELLED"
+	StatusExpired            = "EXPIRED"
 )
 
+// Request moves stock into, out of, or between warehouses. It is a header with
+// one or more lines, each naming an item and quantity. For TRANSFER requests
+// WarehouseID is the source and DestinationWarehouseID the target.
+// Quantity is the total over all lines; ItemID and Item are only set on
+// single-line requests so single-item clients keep working. Serials and
+// LotAllocations cover every line and carry the line they belong to.
+// A rejected request keeps the approver's RejectionReason.
+// A request stays PENDING until every step in Approvals is signed; one pending past
+// its SLA is escalated once (EscalatedAt) and eventually EXPIRED.
+// Inbound and transfer requests complete on approval; approved outbound requests
+// are fulfilled by one or more Shipments and complete once every line is shipped.
 type Request struct {
-	ID         uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	Type       string     `gorm:"size:20;not null" json:"type"`
-	Status     string     `gorm:"size:20;not null;default:'PENDING'" json:"status"`
-	ItemID     uuid.UUID  `gorm:"type:uuid;not null" json:"item_id"`
-	Quantity   int        `gorm:"not null" json:"quantity"`
-	Notes      string     `gorm:"type:text" json:"notes,omitempty"`
-	CreatedBy  uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
-	ApprovedBy *uuid.UUID `gorm:"type:uuid" json:"approved_by,omitempty"`
-	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+	ID                     uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Type                   string     `gorm:"size:20;not null" json:"type"`
+	Status                 string     `gorm:"size:20;not null;default:'PENDING'" json:"status"`
+	ItemID                 *uuid.UUID `gorm:"type:uuid" json:"item_id,omitempty"`
+	WarehouseID            uuid.UUID  `gorm:"type:uuid;not null" json:"warehouse_id"`
+	DestinationWarehouseID *uuid.UUID `gorm:"type:uuid" json:"destination_warehouse_id,omitempty"`
+	Quantity               int        `gorm:"not null" json:"quantity"`
+	Notes                  string     `gorm:"type:text" json:"notes,omitempty"`
+	CreatedBy              uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	ApprovedBy             *uuid.UUID `gorm:"type:uuid" json:"approved_by,omitempty"`
+	RejectionReason        string     `gorm:"type:text" json:"rejection_reason,omitempty"`
+	EscalatedAt            *time.Time `json:"escalated_at,omitempty"`
+	CreatedAt              time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt              time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 
 	// Relations (for preloading)
-	Item     Inventory `gorm:"foreignKey:ItemID" json:"item,omitempty"`
-	Creator  User      `gorm:"foreignKey:CreatedBy" json:"creator,omitempty"`
-	Approver *User     `gorm:"foreignKey:ApprovedBy" json:"approver,omitempty"`
+	Item                 *Inventory          `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+	Warehouse            Warehouse           `gorm:"foreignKey:WarehouseID" json:"warehouse,omitempty"`
+	DestinationWarehouse *Warehouse          `gorm:"foreignKey:DestinationWarehouseID" json:"destination_warehouse,omitempty"`
+	Creator              User                `gorm:"foreignKey:CreatedBy" json:"creator,omitempty"`
+	Approver             *User               `gorm:"foreignKey:ApprovedBy" json:"approver,omitempty"`
+	Lines                []RequestLine       `gorm:"foreignKey:RequestID" json:"lines,omitempty"`
+	LotAllocations       []RequestLot        `gorm:"foreignKey:RequestID" json:"lot_allocations,omitempty"`
+	Serials              []RequestSerial     `gorm:"foreignKey:RequestID" json:"serials,omitempty"`
+	Shipments            []Shipment          `gorm:"foreignKey:RequestID" json:"shipments,omitempty"`
+	Approvals            []RequestApproval   `gorm:"foreignKey:RequestID" json:"approvals,omitempty"`
+	Comments             []RequestComment    `gorm:"foreignKey:RequestID" json:"comments,omitempty"`
+	Attachments          []RequestAttachment `gorm:"foreignKey:RequestID" json:"attachments,omitempty"`
+}
+
+// NextApproval returns the first unsigned step of the approval chain, or nil
+func (r *Request) NextApproval() *RequestApproval {
+	for i := range r.Approvals {
+		if r.Approvals[i].ApprovedBy == nil {
+			return &r.Approvals[i]
+		}
+	}
+	return nil
 }
 
 func (Request) TableName() string {
 	return "requests"
 }
 
+// RequestLine is one item of a request. BinID records the put-away (inbound) or
+// pick (outbound, transfer source) bin chosen at approval; DestinationBinID the
+// put-away bin of a transfer. Inbound lines carry the lot they receive.
+// ReservedQuantity is the part of the source balance the line still holds; it is
+// reserved on creation and released as the line is picked or on rejection.
+// ShippedQuantity is how much of an outbound line has been shipped so far.
+type RequestLine struct {
+	ID               uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RequestID        uuid.UUID  `gorm:"type:uuid;not null" json:"request_id"`
+	LineNo           int        `gorm:"not null" json:"line_no"`
+	ItemID           uuid.UUID  `gorm:"type:uuid;not null" json:"item_id"`
+	Quantity         int        `gorm:"not null" json:"quantity"`
+	ReservedQuantity int        `gorm:"not null;default:0" json:"reserved_quantity"`
+	ShippedQuantity  int        `gorm:"not null;default:0" json:"shipped_quantity"`
+	BinID            *uuid.UUID `gorm:"type:uuid" json:"bin_id,omitempty"`
+	DestinationBinID *uuid.UUID `gorm:"type:uuid" json:"destination_bin_id,omitempty"`
+	LotNumber        string     `gorm:"size:100" json:"lot_number,omitempty"`
+	ManufacturedAt   *time.Time `json:"manufactured_at,omitempty"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	CreatedAt        time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relations (for preloading)
+	Item *Inventory `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+}
+
+func (RequestLine) TableName() string {
+	return "request_lines"
+}
+
+// Outstanding returns the quantity of the line still to be shipped
+func (l RequestLine) Outstanding() int {
+	return l.Quantity - l.ShippedQuantity
+}
+
 // ValidTransition checks if state transition is allowed
 func ValidTransition(from, to string) bool {
 	transitions := map[string][]string{
-		StatusPending:  {StatusApproved, StatusRejected},
-		StatusApproved: {StatusCompleted},
+		StatusPending:            {StatusApproved, StatusRejected, StatusCancelled, StatusExpired},
+		StatusApproved:           {StatusPartiallyFulfilled, StatusCompleted, StatusCancelled},
+		StatusPartiallyFulfilled: {StatusPartiallyFulfilled, StatusCompleted, StatusCancelled},
 	}
 	allowed, ok := transitions[from]
 	if !ok {
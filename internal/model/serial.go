@@ -0,0 +1,71 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Serial number statuses
+const (
+	SerialStatusInStock = "IN_STOCK"
+	SerialStatusShipped = "SHIPPED"
+)
+
+// Serial movement types
+const (
+	SerialMovementReceived    = "RECEIVED"
+	SerialMovementShipped     = "SHIPPED"
+	SerialMovementTransferred = "TRANSFERRED"
+)
+
+// SerialNumber is one unit of a serialized item. WarehouseID is where the unit
+// currently is, or was last seen when it has been shipped.
+type SerialNumber struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ItemID       uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_serial_numbers_item_serial" json:"item_id"`
+	SerialNumber string    `gorm:"size:100;not null;uniqueIndex:idx_serial_numbers_item_serial" json:"serial_number"`
+	WarehouseID  uuid.UUID `gorm:"type:uuid;not null" json:"warehouse_id"`
+	Status       string    `gorm:"size:20;not null" json:"status"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relations (for preloading)
+	Item      *Inventory       `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+	Warehouse *Warehouse       `gorm:"foreignKey:WarehouseID" json:"warehouse,omitempty"`
+	Movements []SerialMovement `gorm:"foreignKey:SerialID" json:"movements,omitempty"`
+}
+
+func (SerialNumber) TableName() string {
+	return "serial_numbers"
+}
+
+// SerialMovement is one entry in the history of a serialized unit
+type SerialMovement struct {
+	ID              uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	SerialID        uuid.UUID  `gorm:"type:uuid;not null" json:"serial_id"`
+	RequestID       uuid.UUID  `gorm:"type:uuid;not null" json:"request_id"`
+	Type            string     `gorm:"size:20;not null" json:"type"`
+	FromWarehouseID *uuid.UUID `gorm:"type:uuid" json:"from_warehouse_id,omitempty"`
+	ToWarehouseID   *uuid.UUID `gorm:"type:uuid" json:"to_warehouse_id,omitempty"`
+	UserID          uuid.UUID  `gorm:"type:uuid;not null" json:"user_id"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (SerialMovement) TableName() string {
+	return "serial_movements"
+}
+
+// RequestSerial is a serial number named on a request line. On outbound
+// requests ShipmentID is set once the unit has been shipped.
+type RequestSerial struct {
+	ID           uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RequestID    uuid.UUID  `gorm:"type:uuid;not null" json:"request_id"`
+	LineID       uuid.UUID  `gorm:"type:uuid;not null" json:"line_id"`
+	SerialNumber string     `gorm:"size:100;not null" json:"serial_number"`
+	ShipmentID   *uuid.UUID `gorm:"type:uuid" json:"shipment_id,omitempty"`
+}
+
+func (RequestSerial) TableName() string {
+	return "request_serials"
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Shipment records stock actually picked and shipped against an approved outbound
+// request. A request may be fulfilled by several shipments; each names the lines
+// it ships and how much of each.
+type Shipment struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RequestID uuid.UUID `gorm:"type:uuid;not null" json:"request_id"`
+	ShippedBy uuid.UUID `gorm:"type:uuid;not null" json:"shipped_by"`
+	Notes     string    `gorm:"type:text" json:"notes,omitempty"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relations (for preloading)
+	Shipper *User          `gorm:"foreignKey:ShippedBy" json:"shipper,omitempty"`
+	Lines   []ShipmentLine `gorm:"foreignKey:ShipmentID" json:"lines,omitempty"`
+}
+
+func (Shipment) TableName() string {
+	return "shipments"
+}
+
+// ShipmentLine is the quantity of one request line shipped, and the bin it was picked from
+type ShipmentLine struct {
+	ID         uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ShipmentID uuid.UUID  `gorm:"type:uuid;not null" json:"shipment_id"`
+	LineID     uuid.UUID  `gorm:"type:uuid;not null" json:"line_id"`
+	Quantity   int        `gorm:"not null" json:"quantity"`
+	BinID      *uuid.UUID `gorm:"type:uuid" json:"bin_id,omitempty"`
+}
+
+func (ShipmentLine) TableName() string {
+	return "shipment_lines"
+}
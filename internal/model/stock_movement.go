@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MovementTypeOpening marks opening stock booked when an item is created.
+// Other movements carry the type of the request that caused them.
+const MovementTypeOpening = "OPENING"
+
+// StockMovement is an append-only ledger entry for one change to an
+// item/warehouse balance, written in the same transaction as the change.
+// Seq is assigned by the database and orders the entries of one balance.
+type StockMovement struct {
+	ID           uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Seq          int64      `gorm:"->" json:"seq"`
+	StockID      uuid.UUID  `gorm:"type:uuid;not null" json:"stock_id"`
+	ItemID       uuid.UUID  `gorm:"type:uuid;not null" json:"item_id"`
+	WarehouseID  uuid.UUID  `gorm:"type:uuid;not null" json:"warehouse_id"`
+	RequestID    *uuid.UUID `gorm:"type:uuid" json:"request_id,omitempty"`
+	Type         string     `gorm:"size:20;not null" json:"type"`
+	Delta        int        `gorm:"not null" json:"delta"`
+	BalanceAfter int        `gorm:"not null" json:"balance_after"`
+	UserID       *uuid.UUID `gorm:"type:uuid" json:"user_id,omitempty"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relations (for preloading)
+	Warehouse *Warehouse `gorm:"foreignKey:WarehouseID" json:"warehouse,omitempty"`
+}
+
+func (StockMovement) TableName() string {
+	return "stock_movements"
+}
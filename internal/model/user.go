@@ -1,6 +1,7 @@
 package model
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,20 +15,61 @@ const (
 	RoleAuditor    = "auditor"
 )
 
+// User statuses; only active users can log in
+const (
+	UserStatusActive      = "active"
+	UserStatusPending     = "pending"
+	UserStatusDeactivated = "deactivated"
+)
+
+// SystemUserID is the user background jobs act as in the audit log; it cannot log in
+var SystemUserID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
 type User struct {
 	ID           uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
 	Name         string    `gorm:"size:255;not null" json:"name"`
 	Email        string    `gorm:"size:255;not null;uniqueIndex" json:"email"`
 	PasswordHash string    `gorm:"size:255;not null" json:"-"`
 	Role         string    `gorm:"size:50;not null;default:'staff'" json:"role"`
+	Status       string    `gorm:"size:20;not null;default:'active'" json:"status"`
 	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// An admin-forced reset blocks login and existing tokens until the user sets a
+	// new password with the one-time reset token
+	PasswordResetRequired bool       `gorm:"not null;default:false" json:"password_reset_required"`
+	ResetTokenHash        *string    `gorm:"size:64" json:"-"`
+	ResetTokenExpiresAt   *time.Time `json:"-"`
+
+	// Set when repeated failed logins lock the account
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
 }
 
 func (User) TableName() string {
 	return "users"
 }
 
+// CheckAccount returns why the user may not log in or use their tokens, if anything
+func (u User) CheckAccount() error {
+	switch {
+	case u.Status == UserStatusPending:
+		return errors.New("account pending activation")
+	case u.Status == UserStatusDeactivated:
+		return errors.New("account deactivated")
+	case u.PasswordResetRequired:
+		return errors.New("password reset required")
+	}
+	return nil
+}
+
+// LockedFor returns how long the account stays locked after the given time, or zero
+func (u User) LockedFor(at time.Time) time.Duration {
+	if u.LockedUntil == nil || !at.Before(*u.LockedUntil) {
+		return 0
+	}
+	return u.LockedUntil.Sub(at)
+}
+
 // ValidRoles returns all valid roles
 func ValidRoles() []string {
 	return []string{RoleStaff, RoleSupervisor, RoleAdmin, RoleAuditor}
@@ -37,3 +79,31 @@ func ValidRoles() []string {
 func CanApprove(role string) bool {
 	return role == RoleSupervisor || role == RoleAdmin
 }
+
+// approvalRank orders the roles that can sign approval steps
+var approvalRank = map[string]int{
+	RoleSupervisor: 1,
+	RoleAdmin:      2,
+}
+
+// CanSignStep checks if the role can sign an approval step that requires the given
+// role; a higher role may sign for a lower one
+func CanSignStep(role, required string) bool {
+	rank, ok := approvalRank[role]
+	return ok && rank >= approvalRank[required]
+}
+
+// NextApprovalTier returns the role ranked right above role among those that sign
+// approval steps; there is none above the top rank
+func NextApprovalTier(role string) (string, bool) {
+	rank, ok := approvalRank[role]
+	if !ok {
+		return "", false
+	}
+	for tier, tierRank := range approvalRank {
+		if tierRank == rank+1 {
+			return tier, true
+		}
+	}
+	return "", false
+}
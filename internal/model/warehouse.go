@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Warehouse struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Code      string    `gorm:"size:50;not null;uniqueIndex" json:"code"`
+	Name      string    `gorm:"size:255;not null" json:"name"`
+	Address   string    `gorm:"type:text" json:"address,omitempty"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (Warehouse) TableName() string {
+	return "warehouses"
+}
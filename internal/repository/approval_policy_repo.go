@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	domainRepo "github.com/senoagung27/warehousex/internal/domain/repository"
+	"github.com/senoagung27/warehousex/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type approvalPolicyRepository struct {
+	db *gorm.DB
+}
+
+func NewApprovalPolicyRepository(db *gorm.DB) domainRepo.ApprovalPolicyRepository {
+	return &approvalPolicyRepository{db: db}
+}
+
+// policySteps preloads a policy's steps in signing order
+func policySteps(db *gorm.DB) *gorm.DB {
+	return db.Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order("step_no ASC")
+	})
+}
+
+func (r *approvalPolicyRepository) Create(policy *model.ApprovalPolicy) error {
+	return r.db.Create(policy).Error
+}
+
+func (r *approvalPolicyRepository) FindByID(id uuid.UUID) (*model.ApprovalPolicy, error) {
+	var policy model.ApprovalPolicy
+	if err := r.db.Scopes(policySteps).Where("id = ?", id).First(&policy).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (r *approvalPolicyRepository) FindAll(activeOnly bool) ([]model.ApprovalPolicy, error) {
+	var policies []model.ApprovalPolicy
+
+	query := r.db.Scopes(policySteps)
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+
+	if err := query.Order("created_at ASC").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (r *approvalPolicyRepository) Update(policy *model.ApprovalPolicy) error {
+	return r.db.Omit(clause.Associations).Save(policy).Error
+}
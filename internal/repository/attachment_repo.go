@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	domainRepo "github.com/senoagung27/warehousex/internal/domain/repository"
+	"github.com/senoagung27/warehousex/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type attachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) domainRepo.AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+func (r *attachmentRepository) CreateWithTx(tx interface{}, attachment *model.RequestAttachment) error {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+	return gormTx.Omit(clause.Associations).Create(attachment).Error
+}
+
+func (r *attachmentRepository) FindByID(requestID, id uuid.UUID) (*model.RequestAttachment, error) {
+	var attachment model.RequestAttachment
+	if err := r.db.Preload("Uploader").
+		Where("id = ? AND request_id = ?", id, requestID).First(&attachment).Error; err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *attachmentRepository) FindByRequest(requestID uuid.UUID) ([]model.RequestAttachment, error) {
+	var attachments []model.RequestAttachment
+	if err := r.db.Preload("Uploader").
+		Where("request_id = ?", requestID).Order("created_at ASC").
+		Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
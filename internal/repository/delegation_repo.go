@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	domainRepo "github.com/senoagung27/warehousex/internal/domain/repository"
+	"github.com/senoagung27/warehousex/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type delegationRepository struct {
+	db *gorm.DB
+}
+
+func NewDelegationRepository(db *gorm.DB) domainRepo.DelegationRepository {
+	return &delegationRepository{db: db}
+}
+
+func (r *delegationRepository) Create(delegation *model.ApprovalDelegation) error {
+	return r.db.Omit(clause.Associations).Create(delegation).Error
+}
+
+func (r *delegationRepository) FindByID(id uuid.UUID) (*model.ApprovalDelegation, error) {
+	var delegation model.ApprovalDelegation
+	if err := r.db.Preload("Delegator").Preload("Delegate").
+		Where("id = ?", id).First(&delegation).Error; err != nil {
+		return nil, err
+	}
+	return &delegation, nil
+}
+
+func (r *delegationRepository) FindByUser(userID *uuid.UUID) ([]model.ApprovalDelegation, error) {
+	var delegations []model.ApprovalDelegation
+
+	query := r.db.Preload("Delegator").Preload("Delegate")
+	if userID != nil {
+		query = query.Where("delegator_id = ? OR delegate_id = ?", *userID, *userID)
+	}
+
+	if err := query.Order("starts_at DESC").Find(&delegations).Error; err != nil {
+		return nil, err
+	}
+	return delegations, nil
+}
+
+func (r *delegationRepository) FindActiveForDelegate(delegateID uuid.UUID, at time.Time) ([]model.ApprovalDelegation, error) {
+	var delegations []model.ApprovalDelegation
+	if err := r.db.Preload("Delegator").
+		Where("delegate_id = ? AND revoked_at IS NULL AND starts_at <= ? AND ends_at > ?", delegateID, at, at).
+		Order("created_at ASC").
+		Find(&delegations).Error; err != nil {
+		return nil, err
+	}
+	return delegations, nil
+}
+
+func (r *delegationRepository) Update(delegation *model.ApprovalDelegation) error {
+	return r.db.Omit(clause.Associations).Save(delegation).Error
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	domainRepo "github.com/senoagung27/warehousex/internal/domain/repository"
@@ -24,27 +25,53 @@ func (r *inventoryRepository) Create(item *model.Inventory) error {
 
 func (r *inventoryRepository) FindByID(id uuid.UUID) (*model.Inventory, error) {
 	var item model.Inventory
-	if err := r.db.Where("id = ?", id).First(&item).Error; err != nil {
+	if err := r.db.Preload("Stocks.Warehouse").
+		Preload("Bins", "quantity > 0").Preload("Bins.Location").
+		Preload("Lots", func(db *gorm.DB) *gorm.DB {
+			return db.Where("quantity > 0").Order("expires_at ASC NULLS LAST")
+		}).
+		Where("id = ?", id).First(&item).Error; err != nil {
 		return nil, err
 	}
 	return &item, nil
 }
 
-func (r *inventoryRepository) FindAll(page, limit int) ([]model.Inventory, int64, error) {
+func (r *inventoryRepository) FindAll(page, limit int, createdBefore *time.Time) ([]model.Inventory, int64, error) {
 	var items []model.Inventory
 	var total int64
 
-	r.db.Model(&model.Inventory{}).Count(&total)
+	query := r.db.Model(&model.Inventory{})
+	if createdBefore != nil {
+		query = query.Where("created_at < ?", *createdBefore)
+	}
+
+	query.Count(&total)
 
 	offset := (page - 1) * limit
-	if err := r.db.Offset(offset).Limit(limit).Order("created_at DESC").Find(&items).Error; err != nil {
+	if err := query.Preload("Stocks").
+		Offset(offset).Limit(limit).Order("created_at DESC").Find(&items).Error; err != nil {
 		return nil, 0, err
 	}
 	return items, total, nil
 }
 
+func (r *inventoryRepository) FindAllWithStocks(createdBefore *time.Time) ([]model.Inventory, error) {
+	var items []model.Inventory
+
+	query := r.db.Model(&model.Inventory{})
+	if createdBefore != nil {
+		query = query.Where("created_at < ?", *createdBefore)
+	}
+
+	if err := query.Preload("Stocks.Warehouse").
+		Order("sku ASC, item_name ASC").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 func (r *inventoryRepository) Update(item *model.Inventory) error {
-	return r.db.Save(item).Error
+	return r.db.Omit(clause.Associations).Save(item).Error
 }
 
 func (r *inventoryRepository) FindByIDForUpdate(tx interface{}, id uuid.UUID) (*model.Inventory, error) {
@@ -66,5 +93,5 @@ func (r *inventoryRepository) UpdateWithTx(tx interface{}, item *model.Inventory
 	if !ok {
 		return fmt.Errorf("invalid transaction type")
 	}
-	return gormTx.Save(item).Error
+	return gormTx.Omit(clause.Associations).Save(item).Error
 }
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	domainRepo "github.com/senoagung27/warehousex/internal/domain/repository"
+	"github.com/senoagung27/warehousex/internal/model"
+	"gorm.io/gorm"
+)
+
+type invitationRepository struct {
+	db *gorm.DB
+}
+
+func NewInvitationRepository(db *gorm.DB) domainRepo.InvitationRepository {
+	return &invitationRepository{db: db}
+}
+
+func (r *invitationRepository) Create(invitation *model.Invitation) error {
+	return r.db.Create(invitation).Error
+}
+
+func (r *invitationRepository) FindByID(id uuid.UUID) (*model.Invitation, error) {
+	var invitation model.Invitation
+	if err := r.db.Where("id = ?", id).First(&invitation).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) FindByTokenHash(tokenHash string) (*model.Invitation, error) {
+	var invitation model.Invitation
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&invitation).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) FindAll(pendingOnly bool) ([]model.Invitation, error) {
+	var invitations []model.Invitation
+
+	query := r.db.Model(&model.Invitation{})
+	if pendingOnly {
+		query = query.Where("accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", time.Now())
+	}
+
+	if err := query.Order("created_at DESC").Find(&invitations).Error; err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+func (r *invitationRepository) Update(invitation *model.Invitation) error {
+	return r.db.Save(invitation).Error
+}
+
+func (r *invitationRepository) AcceptWithTx(tx interface{}, id, userID uuid.UUID, at time.Time) error {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	result := gormTx.Model(&model.Invitation{}).
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{"accepted_at": at, "accepted_by": userID})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != 1 {
+		return fmt.Errorf("invitation already used")
+	}
+	return nil
+}
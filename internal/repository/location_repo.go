@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	domainRepo "github.com/senoagung27/warehousex/internal/domain/repository"
+	"github.com/senoagung27/warehousex/internal/model"
+	"gorm.io/gorm"
+)
+
+type locationRepository struct {
+	db *gorm.DB
+}
+
+func NewLocationRepository(db *gorm.DB) domainRepo.LocationRepository {
+	return &locationRepository{db: db}
+}
+
+func (r *locationRepository) Create(location *model.Location) error {
+	return r.db.Create(location).Error
+}
+
+func (r *locationRepository) FindByID(id uuid.UUID) (*model.Location, error) {
+	var location model.Location
+	if err := r.db.Where("id = ?", id).First(&location).Error; err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+func (r *locationRepository) FindByWarehouse(warehouseID uuid.UUID, locationType string) ([]model.Location, error) {
+	var locations []model.Location
+
+	query := r.db.Where("warehouse_id = ?", warehouseID)
+	if locationType != "" {
+		query = query.Where("type = ?", locationType)
+	}
+
+	if err := query.Order("path ASC").Find(&locations).Error; err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+func (r *locationRepository) FindByPath(warehouseID uuid.UUID, path string) (*model.Location, error) {
+	var location model.Location
+	if err := r.db.Where("warehouse_id = ? AND path = ?", warehouseID, path).First(&location).Error; err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	domainRepo "github.com/senoagung27/warehousex/internal/domain/repository"
+	"github.com/senoagung27/warehousex/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type lotRepository struct {
+	db *gorm.DB
+}
+
+func NewLotRepository(db *gorm.DB) domainRepo.LotRepository {
+	return &lotRepository{db: db}
+}
+
+func (r *lotRepository) FindByItem(itemID uuid.UUID) ([]model.Lot, error) {
+	var lots []model.Lot
+	if err := r.db.Where("item_id = ? AND quantity > 0", itemID).
+		Order("expires_at ASC NULLS LAST, created_at ASC").
+		Find(&lots).Error; err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
+
+func (r *lotRepository) FindExpiring(before time.Time, warehouseID *uuid.UUID) ([]model.Lot, error) {
+	var lots []model.Lot
+
+	query := r.db.Preload("Item").Preload("Warehouse").
+		Where("quantity > 0 AND expires_at IS NOT NULL AND expires_at <= ?", before)
+	if warehouseID != nil {
+		query = query.Where("warehouse_id = ?", *warehouseID)
+	}
+
+	if err := query.Order("expires_at ASC").Find(&lots).Error; err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
+
+func (r *lotRepository) FindOrCreateForUpdate(tx interface{}, lot *model.Lot) (*model.Lot, error) {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return nil, fmt.Errorf("invalid transaction type")
+	}
+
+	if err := gormTx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "item_id"}, {Name: "warehouse_id"}, {Name: "lot_number"}},
+		DoNothing: true,
+	}).Omit(clause.Associations).Create(lot).Error; err != nil {
+		return nil, err
+	}
+
+	var locked model.Lot
+	if err := gormTx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("item_id = ? AND warehouse_id = ? AND lot_number = ?", lot.ItemID, lot.WarehouseID, lot.LotNumber).
+		First(&locked).Error; err != nil {
+		return nil, err
+	}
+	return &locked, nil
+}
+
+func (r *lotRepository) FindAvailableForUpdate(tx interface{}, itemID, warehouseID uuid.UUID) ([]model.Lot, error) {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return nil, fmt.Errorf("invalid transaction type")
+	}
+
+	var lots []model.Lot
+	if err := gormTx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("item_id = ? AND warehouse_id = ? AND quantity > 0", itemID, warehouseID).
+		Where("expires_at IS NULL OR expires_at > NOW()").
+		Order("expires_at ASC NULLS LAST, created_at ASC").
+		Find(&lots).Error; err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
+
+func (r *lotRepository) SumQuantityWithTx(tx interface{}, itemID, warehouseID uuid.UUID) (int, error) {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return 0, fmt.Errorf("invalid transaction type")
+	}
+
+	var total int
+	if err := gormTx.Model(&model.Lot{}).
+		Select("COALESCE(SUM(quantity), 0)").
+		Where("item_id = ? AND warehouse_id = ?", itemID, warehouseID).
+		Scan(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (r *lotRepository) UpdateWithTx(tx interface{}, lot *model.Lot) error {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+	return gormTx.Omit(clause.Associations).Save(lot).Error
+}
+
+func (r *lotRepository) CreateAllocationsWithTx(tx interface{}, allocations []model.RequestLot) error {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+	if len(allocations) == 0 {
+		return nil
+	}
+	return gormTx.Omit(clause.Associations).Create(&allocations).Error
+}
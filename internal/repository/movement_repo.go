@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	domainRepo "github.com/senoagung27/warehousex/internal/domain/repository"
+	"github.com/senoagung27/warehousex/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type movementRepository struct {
+	db *gorm.DB
+}
+
+func NewMovementRepository(db *gorm.DB) domainRepo.MovementRepository {
+	return &movementRepository{db: db}
+}
+
+func (r *movementRepository) CreateWithTx(tx interface{}, movement *model.StockMovement) error {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+	return gormTx.Omit(clause.Associations).Create(movement).Error
+}
+
+func (r *movementRepository) BalancesAsOf(itemIDs []uuid.UUID, asOf time.Time) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		StockID      uuid.UUID
+		BalanceAfter int
+	}
+
+	query := r.db.Model(&model.StockMovement{}).
+		Select("DISTINCT ON (stock_id) stock_id, balance_after").
+		Where("created_at < ?", asOf)
+	if itemIDs != nil {
+		query = query.Where("item_id IN ?", itemIDs)
+	}
+
+	if err := query.Order("stock_id, seq DESC").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	balances := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		balances[row.StockID] = row.BalanceAfter
+	}
+	return balances, nil
+}
+
+func (r *movementRepository) FindByItem(page, limit int, itemID uuid.UUID, warehouseID *uuid.UUID, from, to *time.Time) ([]model.StockMovement, int64, error) {
+	var movements []model.StockMovement
+	var total int64
+
+	query := r.db.Model(&model.StockMovement{}).Where("item_id = ?", itemID)
+
+	if warehouseID != nil {
+		query = query.Where("warehouse_id = ?", *warehouseID)
+	}
+	if from != nil {
+		query = query.Where("created_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("created_at < ?", *to)
+	}
+
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	if err := query.Preload("Warehouse").
+		Offset(offset).Limit(limit).Order("seq DESC").
+		Find(&movements).Error; err != nil {
+		return nil, 0, err
+	}
+	return movements, total, nil
+}
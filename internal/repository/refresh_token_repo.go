@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	domainRepo "github.com/senoagung27/warehousex/internal/domain/repository"
+	"github.com/senoagung27/warehousex/internal/model"
+	"gorm.io/gorm"
+)
+
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) domainRepo.RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+func (r *refreshTokenRepository) Create(token *model.RefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *refreshTokenRepository) CreateWithTx(tx interface{}, token *model.RefreshToken) error {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+	return gormTx.Create(token).Error
+}
+
+func (r *refreshTokenRepository) FindByTokenHash(tokenHash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *refreshTokenRepository) FindByAccessJTI(jti string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	if err := r.db.Where("access_jti = ?", jti).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *refreshTokenRepository) MarkUsedWithTx(tx interface{}, id uuid.UUID, at time.Time) error {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	result := gormTx.Model(&model.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("used_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != 1 {
+		return domainRepo.ErrRefreshTokenUsed
+	}
+	return nil
+}
+
+func (r *refreshTokenRepository) RevokeFamily(familyID uuid.UUID, at time.Time) ([]model.RefreshToken, error) {
+	return r.revoke("family_id", familyID, at)
+}
+
+func (r *refreshTokenRepository) RevokeByUser(userID uuid.UUID, at time.Time) ([]model.RefreshToken, error) {
+	return r.revoke("user_id", userID, at)
+}
+
+// revoke revokes the tokens whose column matches id
+func (r *refreshTokenRepository) revoke(column string, id uuid.UUID, at time.Time) ([]model.RefreshToken, error) {
+	var tokens []model.RefreshToken
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.RefreshToken{}).
+			Where(column+" = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", at).Error; err != nil {
+			return err
+		}
+		return tx.Where(column+" = ? AND access_expires_at > ?", id, at).Find(&tokens).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
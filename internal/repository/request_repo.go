@@ -2,11 +2,13 @@ package repository
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	domainRepo "github.com/senoagung27/warehousex/internal/domain/repository"
 	"github.com/senoagung27/warehousex/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type requestRepository struct {
@@ -17,13 +19,55 @@ func NewRequestRepository(db *gorm.DB) domainRepo.RequestRepository {
 	return &requestRepository{db: db}
 }
 
+// requestRelations preloads the relations returned with a request
+func requestRelations(db *gorm.DB) *gorm.DB {
+	return db.Preload("Item.Stocks").
+		Preload("Lines", func(db *gorm.DB) *gorm.DB {
+			return db.Order("line_no ASC")
+		}).
+		Preload("Lines.Item.Stocks").
+		Preload("Warehouse").Preload("DestinationWarehouse").
+		Preload("Creator").Preload("Approver").
+		Preload("LotAllocations.Lot").
+		Preload("Serials", func(db *gorm.DB) *gorm.DB {
+			return db.Order("serial_number ASC")
+		}).
+		Preload("Shipments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Shipments.Lines").Preload("Shipments.Shipper").
+		Preload("Approvals", approvalOrder).Preload("Approvals.Approver").
+		Preload("Comments", commentOrder).Preload("Comments.Author").
+		Preload("Attachments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		})
+}
+
+// commentOrder orders a request's comment thread oldest first
+func commentOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("created_at ASC")
+}
+
+// approvalOrder orders a request's approval steps in signing order
+func approvalOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("step_no ASC")
+}
+
 func (r *requestRepository) Create(req *model.Request) error {
 	return r.db.Create(req).Error
 }
 
+func (r *requestRepository) CreateWithTx(tx interface{}, req *model.Request) error {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+	return gormTx.Create(req).Error
+}
+
 func (r *requestRepository) FindByID(id uuid.UUID) (*model.Request, error) {
 	var req model.Request
-	if err := r.db.Preload("Item").Preload("Creator").Preload("Approver").
+	if err := r.db.Scopes(requestRelations).
 		Where("id = ?", id).First(&req).Error; err != nil {
 		return nil, err
 	}
@@ -43,7 +87,7 @@ func (r *requestRepository) FindAll(page, limit int, filters map[string]interfac
 	query.Count(&total)
 
 	offset := (page - 1) * limit
-	if err := query.Preload("Item").Preload("Creator").Preload("Approver").
+	if err := query.Scopes(requestRelations).
 		Offset(offset).Limit(limit).Order("created_at DESC").
 		Find(&requests).Error; err != nil {
 		return nil, 0, err
@@ -52,7 +96,37 @@ func (r *requestRepository) FindAll(page, limit int, filters map[string]interfac
 }
 
 func (r *requestRepository) Update(req *model.Request) error {
-	return r.db.Save(req).Error
+	return r.db.Omit(clause.Associations).Save(req).Error
+}
+
+func (r *requestRepository) FindPendingBefore(reqType string, before time.Time, unescalatedOnly bool, limit int) ([]model.Request, error) {
+	var requests []model.Request
+
+	query := r.db.Preload("Lines").Preload("Approvals", approvalOrder).
+		Where("type = ? AND status = ? AND created_at < ?", reqType, model.StatusPending, before)
+	if unescalatedOnly {
+		query = query.Where("escalated_at IS NULL")
+	}
+
+	if err := query.Order("created_at ASC").Limit(limit).Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+func (r *requestRepository) MarkEscalatedWithTx(tx interface{}, id uuid.UUID, at time.Time) (bool, error) {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return false, fmt.Errorf("invalid transaction type")
+	}
+
+	result := gormTx.Model(&model.Request{}).
+		Where("id = ? AND status = ? AND escalated_at IS NULL", id, model.StatusPending).
+		Update("escalated_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
 }
 
 func (r *requestRepository) FindByIDWithTx(tx interface{}, id uuid.UUID) (*model.Request, error) {
@@ -68,10 +142,86 @@ func (r *requestRepository) FindByIDWithTx(tx interface{}, id uuid.UUID) (*model
 	return &req, nil
 }
 
+func (r *requestRepository) FindByIDForUpdate(tx interface{}, id uuid.UUID) (*model.Request, error) {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return nil, fmt.Errorf("invalid transaction type")
+	}
+
+	var req model.Request
+	if err := gormTx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Lines").Preload("Serials").Preload("Approvals", approvalOrder).
+		Where("id = ?", id).First(&req).Error; err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// UpdateWithTx saves the request header and its lines, which carry the bins and
+// reservations that change on approval
 func (r *requestRepository) UpdateWithTx(tx interface{}, req *model.Request) error {
 	gormTx, ok := tx.(*gorm.DB)
 	if !ok {
 		return fmt.Errorf("invalid transaction type")
 	}
-	return gormTx.Save(req).Error
+	if err := gormTx.Omit(clause.Associations).Save(req).Error; err != nil {
+		return err
+	}
+	for i := range req.Lines {
+		if err := gormTx.Omit(clause.Associations).Save(&req.Lines[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *requestRepository) CreateShipmentWithTx(tx interface{}, shipment *model.Shipment) error {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+	return gormTx.Omit("Shipper").Create(shipment).Error
+}
+
+func (r *requestRepository) ShipSerialsWithTx(tx interface{}, lineID, shipmentID uuid.UUID, serials []string) error {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	result := gormTx.Model(&model.RequestSerial{}).
+		Where("line_id = ? AND serial_number IN ? AND shipment_id IS NULL", lineID, serials).
+		Update("shipment_id", shipmentID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != int64(len(serials)) {
+		return fmt.Errorf("serial numbers already shipped")
+	}
+	return nil
+}
+
+func (r *requestRepository) CreateCommentWithTx(tx interface{}, comment *model.RequestComment) error {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+	return gormTx.Omit("Author").Create(comment).Error
+}
+
+func (r *requestRepository) FindComments(requestID uuid.UUID) ([]model.RequestComment, error) {
+	var comments []model.RequestComment
+	if err := r.db.Scopes(commentOrder).Preload("Author").
+		Where("request_id = ?", requestID).Find(&comments).Error; err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (r *requestRepository) SignApprovalWithTx(tx interface{}, approval *model.RequestApproval) error {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+	return gormTx.Omit(clause.Associations).Save(approval).Error
 }
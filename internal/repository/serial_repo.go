@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	domainRepo "github.com/senoagung27/warehousex/internal/domain/repository"
+	"github.com/senoagung27/warehousex/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type serialRepository struct {
+	db *gorm.DB
+}
+
+func NewSerialRepository(db *gorm.DB) domainRepo.SerialRepository {
+	return &serialRepository{db: db}
+}
+
+func (r *serialRepository) FindBySerial(serial string, itemID *uuid.UUID) ([]model.SerialNumber, error) {
+	var serials []model.SerialNumber
+
+	query := r.db.Preload("Item").Preload("Warehouse").
+		Preload("Movements", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Where("serial_number = ?", serial)
+	if itemID != nil {
+		query = query.Where("item_id = ?", *itemID)
+	}
+
+	if err := query.Find(&serials).Error; err != nil {
+		return nil, err
+	}
+	return serials, nil
+}
+
+func (r *serialRepository) FindByItemAndSerials(itemID uuid.UUID, serials []string) ([]model.SerialNumber, error) {
+	var units []model.SerialNumber
+	if err := r.db.Where("item_id = ? AND serial_number IN ?", itemID, serials).
+		Find(&units).Error; err != nil {
+		return nil, err
+	}
+	return units, nil
+}
+
+func (r *serialRepository) FindForUpdate(tx interface{}, itemID uuid.UUID, serials []string) ([]model.SerialNumber, error) {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return nil, fmt.Errorf("invalid transaction type")
+	}
+
+	var units []model.SerialNumber
+	if err := gormTx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("item_id = ? AND serial_number IN ?", itemID, serials).
+		Order("serial_number ASC").
+		Find(&units).Error; err != nil {
+		return nil, err
+	}
+	return units, nil
+}
+
+func (r *serialRepository) SaveWithTx(tx interface{}, serial *model.SerialNumber) error {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+	return gormTx.Omit(clause.Associations).Save(serial).Error
+}
+
+func (r *serialRepository) CreateMovementsWithTx(tx interface{}, movements []model.SerialMovement) error {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+	if len(movements) == 0 {
+		return nil
+	}
+	return gormTx.Create(&movements).Error
+}
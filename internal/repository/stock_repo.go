@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	domainRepo "github.com/senoagung27/warehousex/internal/domain/repository"
+	"github.com/senoagung27/warehousex/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type stockRepository struct {
+	db *gorm.DB
+}
+
+func NewStockRepository(db *gorm.DB) domainRepo.StockRepository {
+	return &stockRepository{db: db}
+}
+
+func (r *stockRepository) FindByItem(itemID uuid.UUID) ([]model.InventoryStock, error) {
+	var stocks []model.InventoryStock
+	if err := r.db.Preload("Warehouse").
+		Where("item_id = ?", itemID).Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+	return stocks, nil
+}
+
+func (r *stockRepository) FindByItemAndWarehouse(itemID, warehouseID uuid.UUID) (*model.InventoryStock, error) {
+	var stock model.InventoryStock
+	if err := r.db.Where("item_id = ? AND warehouse_id = ?", itemID, warehouseID).
+		First(&stock).Error; err != nil {
+		return nil, err
+	}
+	return &stock, nil
+}
+
+func (r *stockRepository) FindForUpdate(tx interface{}, itemID, warehouseID uuid.UUID) (*model.InventoryStock, error) {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return nil, fmt.Errorf("invalid transaction type")
+	}
+
+	var stock model.InventoryStock
+	if err := gormTx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("item_id = ? AND warehouse_id = ?", itemID, warehouseID).
+		First(&stock).Error; err != nil {
+		return nil, err
+	}
+	return &stock, nil
+}
+
+func (r *stockRepository) FindOrCreateForUpdate(tx interface{}, itemID, warehouseID uuid.UUID) (*model.InventoryStock, error) {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return nil, fmt.Errorf("invalid transaction type")
+	}
+
+	stock := &model.InventoryStock{
+		ID:          uuid.New(),
+		ItemID:      itemID,
+		WarehouseID: warehouseID,
+		Version:     1,
+	}
+	if err := gormTx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "item_id"}, {Name: "warehouse_id"}},
+		DoNothing: true,
+	}).Create(stock).Error; err != nil {
+		return nil, err
+	}
+
+	return r.FindForUpdate(gormTx, itemID, warehouseID)
+}
+
+func (r *stockRepository) UpdateWithTx(tx interface{}, stock *model.InventoryStock) error {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+	return gormTx.Omit(clause.Associations).Save(stock).Error
+}
+
+func (r *stockRepository) FindBinsByItem(itemID uuid.UUID, warehouseID *uuid.UUID) ([]model.BinStock, error) {
+	var bins []model.BinStock
+
+	query := r.db.Preload("Location").
+		Joins("JOIN locations ON locations.id = bin_stocks.location_id").
+		Where("bin_stocks.item_id = ? AND bin_stocks.quantity > 0", itemID)
+	if warehouseID != nil {
+		query = query.Where("bin_stocks.warehouse_id = ?", *warehouseID)
+	}
+
+	if err := query.Order("locations.path ASC").Find(&bins).Error; err != nil {
+		return nil, err
+	}
+	return bins, nil
+}
+
+func (r *stockRepository) FindBinForUpdate(tx interface{}, itemID, locationID uuid.UUID) (*model.BinStock, error) {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return nil, fmt.Errorf("invalid transaction type")
+	}
+
+	var bin model.BinStock
+	if err := gormTx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("item_id = ? AND location_id = ?", itemID, locationID).
+		First(&bin).Error; err != nil {
+		return nil, err
+	}
+	return &bin, nil
+}
+
+func (r *stockRepository) FindOrCreateBinForUpdate(tx interface{}, itemID, warehouseID, locationID uuid.UUID) (*model.BinStock, error) {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return nil, fmt.Errorf("invalid transaction type")
+	}
+
+	bin := &model.BinStock{
+		ID:          uuid.New(),
+		ItemID:      itemID,
+		WarehouseID: warehouseID,
+		LocationID:  locationID,
+		Version:     1,
+	}
+	if err := gormTx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "item_id"}, {Name: "location_id"}},
+		DoNothing: true,
+	}).Create(bin).Error; err != nil {
+		return nil, err
+	}
+
+	return r.FindBinForUpdate(gormTx, itemID, locationID)
+}
+
+func (r *stockRepository) UpdateBinWithTx(tx interface{}, bin *model.BinStock) error {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+	return gormTx.Omit(clause.Associations).Save(bin).Error
+}
+
+func (r *stockRepository) SumBinnedWithTx(tx interface{}, itemID, warehouseID uuid.UUID) (int, error) {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return 0, fmt.Errorf("invalid transaction type")
+	}
+
+	var total int
+	if err := gormTx.Model(&model.BinStock{}).
+		Select("COALESCE(SUM(quantity), 0)").
+		Where("item_id = ? AND warehouse_id = ?", itemID, warehouseID).
+		Scan(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
@@ -1,6 +1,10 @@
 package repository
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/google/uuid"
 	domainRepo "github.com/senoagung27/warehousex/internal/domain/repository"
 	"github.com/senoagung27/warehousex/internal/model"
@@ -19,6 +23,14 @@ func (r *userRepository) Create(user *model.User) error {
 	return r.db.Create(user).Error
 }
 
+func (r *userRepository) CreateWithTx(tx interface{}, user *model.User) error {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+	return gormTx.Create(user).Error
+}
+
 func (r *userRepository) FindByID(id uuid.UUID) (*model.User, error) {
 	var user model.User
 	if err := r.db.Where("id = ?", id).First(&user).Error; err != nil {
@@ -35,15 +47,51 @@ func (r *userRepository) FindByEmail(email string) (*model.User, error) {
 	return &user, nil
 }
 
-func (r *userRepository) FindAll(page, limit int) ([]model.User, int64, error) {
+func (r *userRepository) FindByResetTokenHash(tokenHash string) (*model.User, error) {
+	var user model.User
+	if err := r.db.Where("reset_token_hash = ?", tokenHash).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) FindAll(page, limit int, search, role, status string) ([]model.User, int64, error) {
 	var users []model.User
 	var total int64
 
-	r.db.Model(&model.User{}).Count(&total)
+	query := r.db.Model(&model.User{})
+	if search != "" {
+		pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(search) + "%"
+		query = query.Where("name ILIKE ? OR email ILIKE ?", pattern, pattern)
+	}
+	if role != "" {
+		query = query.Where("role = ?", role)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	query.Count(&total)
 
 	offset := (page - 1) * limit
-	if err := r.db.Offset(offset).Limit(limit).Order("created_at DESC").Find(&users).Error; err != nil {
+	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&users).Error; err != nil {
 		return nil, 0, err
 	}
 	return users, total, nil
 }
+
+func (r *userRepository) FindByRole(role string) ([]model.User, error) {
+	var users []model.User
+	if err := r.db.Where("role = ?", role).Order("name ASC").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *userRepository) Update(user *model.User) error {
+	return r.db.Save(user).Error
+}
+
+func (r *userRepository) SetLockedUntil(id uuid.UUID, lockedUntil *time.Time) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).Update("locked_until", lockedUntil).Error
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	domainRepo "github.com/senoagung27/warehousex/internal/domain/repository"
+	"github.com/senoagung27/warehousex/internal/model"
+	"gorm.io/gorm"
+)
+
+type warehouseRepository struct {
+	db *gorm.DB
+}
+
+func NewWarehouseRepository(db *gorm.DB) domainRepo.WarehouseRepository {
+	return &warehouseRepository{db: db}
+}
+
+func (r *warehouseRepository) Create(warehouse *model.Warehouse) error {
+	return r.db.Create(warehouse).Error
+}
+
+func (r *warehouseRepository) FindByID(id uuid.UUID) (*model.Warehouse, error) {
+	var warehouse model.Warehouse
+	if err := r.db.Where("id = ?", id).First(&warehouse).Error; err != nil {
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+func (r *warehouseRepository) FindByCode(code string) (*model.Warehouse, error) {
+	var warehouse model.Warehouse
+	if err := r.db.Where("code = ?", code).First(&warehouse).Error; err != nil {
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+func (r *warehouseRepository) FindAll(page, limit int) ([]model.Warehouse, int64, error) {
+	var warehouses []model.Warehouse
+	var total int64
+
+	r.db.Model(&model.Warehouse{}).Count(&total)
+
+	offset := (page - 1) * limit
+	if err := r.db.Offset(offset).Limit(limit).Order("code ASC").Find(&warehouses).Error; err != nil {
+		return nil, 0, err
+	}
+	return warehouses, total, nil
+}
+
+func (r *warehouseRepository) Update(warehouse *model.Warehouse) error {
+	return r.db.Save(warehouse).Error
+}
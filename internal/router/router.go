@@ -1,6 +1,7 @@
 package router
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -9,38 +10,65 @@ import (
 )
 
 type Router struct {
-	Engine              *gin.Engine
-	authController      *controller.AuthController
-	inventoryController *controller.InventoryController
-	requestController   *controller.RequestController
-	auditController     *controller.AuditController
-	jwtSecret           string
+	Engine               *gin.Engine
+	authController       *controller.AuthController
+	inventoryController  *controller.InventoryController
+	warehouseController  *controller.WarehouseController
+	requestController    *controller.RequestController
+	policyController     *controller.ApprovalPolicyController
+	delegationController *controller.DelegationController
+	attachmentController *controller.AttachmentController
+	auditController      *controller.AuditController
+	invitationController *controller.InvitationController
+	userController       *controller.UserController
+	jwtAuth              gin.HandlerFunc
+	idempotency          gin.HandlerFunc
 }
 
 func NewRouter(
 	authController *controller.AuthController,
 	inventoryController *controller.InventoryController,
+	warehouseController *controller.WarehouseController,
 	requestController *controller.RequestController,
+	policyController *controller.ApprovalPolicyController,
+	delegationController *controller.DelegationController,
+	attachmentController *controller.AttachmentController,
 	auditController *controller.AuditController,
-	jwtSecret string,
+	invitationController *controller.InvitationController,
+	userController *controller.UserController,
+	jwtAuth gin.HandlerFunc,
 	ginMode string,
-) *Router {
+	trustedProxies []string,
+	idempotency gin.HandlerFunc,
+) (*Router, error) {
 	gin.SetMode(ginMode)
 	engine := gin.New()
+	// The login throttle keys on the client IP, so forwarded headers are only
+	// believed from the configured proxies
+	if err := engine.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
 	engine.Use(gin.Recovery())
 	engine.Use(gin.Logger())
 
 	r := &Router{
-		Engine:              engine,
-		authController:      authController,
-		inventoryController: inventoryController,
-		requestController:   requestController,
-		auditController:     auditController,
-		jwtSecret:           jwtSecret,
+		Engine:               engine,
+		authController:       authController,
+		inventoryController:  inventoryController,
+		warehouseController:  warehouseController,
+		requestController:    requestController,
+		policyController:     policyController,
+		delegationController: delegationController,
+		attachmentController: attachmentController,
+		auditController:      auditController,
+		invitationController: invitationController,
+		userController:       userController,
+		jwtAuth:              jwtAuth,
+		idempotency:          idempotency,
 	}
 
 	r.setupRoutes()
-	return r
+	return r, nil
 }
 
 func (r *Router) setupRoutes() {
@@ -53,38 +81,118 @@ func (r *Router) setupRoutes() {
 		})
 	})
 
+	// Public keys for services verifying our tokens
+	r.Engine.GET("/.well-known/jwks.json", r.authController.JWKS)
+
 	// ========== API v1 ==========
 	v1 := r.Engine.Group("/api/v1")
 
-	// --- Auth (public) ---
+	// --- Auth (public, except logout) ---
 	auth := v1.Group("/auth")
 	{
 		auth.POST("/register", r.authController.Register)
 		auth.POST("/login", r.authController.Login)
+		auth.POST("/accept-invitation", r.authController.AcceptInvitation)
+		auth.POST("/reset-password", r.authController.ResetPassword)
+		auth.POST("/refresh", r.authController.Refresh)
+		auth.POST("/logout", r.jwtAuth, r.authController.Logout)
 	}
 
 	// --- Protected routes ---
 	protected := v1.Group("")
-	protected.Use(middleware.JWTAuth(r.jwtSecret))
+	protected.Use(r.jwtAuth)
 
 	// --- Inventory ---
 	inventory := protected.Group("/inventory")
 	{
 		inventory.GET("", r.inventoryController.GetAll)
+		inventory.GET("/export", r.inventoryController.Export)
 		inventory.GET("/:id", r.inventoryController.GetByID)
+		inventory.GET("/:id/bins", r.inventoryController.GetBins)
+		inventory.GET("/:id/movements", r.inventoryController.GetMovements)
 		inventory.POST("", middleware.RequireRole("admin"), r.inventoryController.Create)
 		inventory.PUT("/:id", middleware.RequireRole("admin"), r.inventoryController.Update)
 	}
 
-	// --- Requests (Inbound / Outbound) ---
+	// --- Lots ---
+	lots := protected.Group("/lots")
+	{
+		lots.GET("/expiring", r.inventoryController.GetExpiringLots)
+	}
+
+	// --- Serial Numbers ---
+	serials := protected.Group("/serials")
+	{
+		serials.GET("/:serial", r.inventoryController.GetSerial)
+	}
+
+	// --- Warehouses ---
+	warehouses := protected.Group("/warehouses")
+	{
+		warehouses.GET("", r.warehouseController.GetAll)
+		warehouses.GET("/:id", r.warehouseController.GetByID)
+		warehouses.POST("", middleware.RequireRole("admin"), r.warehouseController.Create)
+		warehouses.PUT("/:id", middleware.RequireRole("admin"), r.warehouseController.Update)
+		warehouses.GET("/:id/locations", r.warehouseController.GetLocations)
+		warehouses.POST("/:id/locations", middleware.RequireRole("admin"), r.warehouseController.CreateLocation)
+	}
+
+	// --- Requests (Inbound / Outbound / Transfer) ---
 	requests := protected.Group("/requests")
 	{
 		requests.GET("", r.requestController.GetAll)
 		requests.GET("/:id", r.requestController.GetByID)
-		requests.POST("/inbound", middleware.RequireRole("staff"), r.requestController.CreateInbound)
-		requests.POST("/outbound", middleware.RequireRole("staff"), r.requestController.CreateOutbound)
-		requests.PUT("/:id/approve", middleware.RequireRoles("supervisor", "admin"), r.requestController.Approve)
-		requests.PUT("/:id/reject", middleware.RequireRoles("supervisor", "admin"), r.requestController.Reject)
+		requests.POST("/inbound", middleware.RequireRole("staff"), r.idempotency, r.requestController.CreateInbound)
+		requests.POST("/outbound", middleware.RequireRole("staff"), r.idempotency, r.requestController.CreateOutbound)
+		requests.POST("/transfer", middleware.RequireRole("staff"), r.requestController.CreateTransfer)
+		// Staff may approve or reject as a delegate; the service checks the authority used
+		requests.PUT("/:id/approve", middleware.RequireRole("staff"), r.idempotency, r.requestController.Approve)
+		requests.PUT("/:id/reject", middleware.RequireRole("staff"), r.idempotency, r.requestController.Reject)
+		requests.POST("/bulk-decision", middleware.RequireRole("staff"), r.requestController.BulkDecide)
+		requests.PUT("/:id/cancel", middleware.RequireRole("staff"), r.requestController.Cancel)
+		requests.POST("/:id/shipments", middleware.RequireRole("staff"), r.requestController.Ship)
+		requests.GET("/:id/comments", r.requestController.GetComments)
+		requests.POST("/:id/comments", middleware.RequireRole("staff"), r.requestController.AddComment)
+		// Downloads are further limited to the creator, approvers and auditors by the service
+		requests.GET("/:id/attachments", r.attachmentController.GetAll)
+		requests.POST("/:id/attachments", middleware.RequireRole("staff"), r.attachmentController.Upload)
+		requests.GET("/:id/attachments/:attachment_id", r.attachmentController.Download)
+	}
+
+	// --- Approval Policies ---
+	policies := protected.Group("/approval-policies")
+	{
+		policies.GET("", middleware.RequireRoles("supervisor", "admin"), r.policyController.GetAll)
+		policies.POST("", middleware.RequireRole("admin"), r.policyController.Create)
+		policies.DELETE("/:id", middleware.RequireRole("admin"), r.policyController.Deactivate)
+	}
+
+	// --- Approval Delegations ---
+	delegations := protected.Group("/delegations")
+	{
+		delegations.GET("", middleware.RequireRole("staff"), r.delegationController.GetAll)
+		delegations.POST("", middleware.RequireRoles("supervisor", "admin"), r.delegationController.Create)
+		delegations.DELETE("/:id", middleware.RequireRoles("supervisor", "admin"), r.delegationController.Revoke)
+	}
+
+	// --- Users ---
+	users := protected.Group("/users")
+	{
+		users.GET("", middleware.RequireRole("admin"), r.userController.GetAll)
+		users.GET("/:id", middleware.RequireRole("admin"), r.userController.GetByID)
+		users.PUT("/:id/role", middleware.RequireRole("admin"), r.userController.UpdateRole)
+		users.POST("/:id/activate", middleware.RequireRole("admin"), r.userController.Activate)
+		users.POST("/:id/deactivate", middleware.RequireRole("admin"), r.userController.Deactivate)
+		users.POST("/:id/unlock", middleware.RequireRole("admin"), r.userController.Unlock)
+		users.POST("/:id/reset-password", middleware.RequireRole("admin"), r.userController.ForcePasswordReset)
+	}
+
+	// --- Invitations ---
+	invitations := protected.Group("/invitations")
+	{
+		invitations.GET("", middleware.RequireRole("admin"), r.invitationController.GetAll)
+		invitations.POST("", middleware.RequireRole("admin"), r.invitationController.Create)
+		invitations.DELETE("/:id", middleware.RequireRole("admin"), r.invitationController.Revoke)
 	}
 
 	// --- Audit Logs ---
@@ -0,0 +1,112 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/config"
+	"github.com/senoagung27/warehousex/internal/infrastructure"
+	"go.uber.org/zap"
+)
+
+// leaderKey is the lock held by the replica that runs the jobs
+const leaderKey = "leader:scheduler"
+
+// SLAProcessor escalates and expires requests pending past their SLA
+type SLAProcessor interface {
+	ProcessSLAs(ctx context.Context, slas map[string]config.SLAConfig, now time.Time) error
+}
+
+// Scheduler runs the background jobs on one replica at a time. Every replica ticks;
+// only the one holding the leadership lock does the work. The lock is taken from
+// the configured lock backend and renewed like any lease, so another replica takes
+// over once the leader stops renewing it.
+type Scheduler struct {
+	id        string
+	cfg       config.SchedulerConfig
+	processor SLAProcessor
+	locker    infrastructure.Locker
+	lease     *infrastructure.Lease
+	log       *zap.Logger
+}
+
+func NewScheduler(cfg config.SchedulerConfig, processor SLAProcessor, locker infrastructure.Locker, log *zap.Logger) *Scheduler {
+	return &Scheduler{
+		id:        uuid.New().String(),
+		cfg:       cfg,
+		processor: processor,
+		locker:    locker,
+		log:       log,
+	}
+}
+
+// Run ticks until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	s.log.Info("Scheduler started",
+		zap.String("instance_id", s.id),
+		zap.Duration("interval", s.cfg.Interval),
+	)
+
+	for {
+		s.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			s.resign()
+			s.log.Info("Scheduler stopped", zap.String("instance_id", s.id))
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) tick(ctx context.Context) {
+	if !s.lead(ctx) {
+		return
+	}
+
+	if err := s.processor.ProcessSLAs(ctx, s.cfg.SLAs, time.Now()); err != nil {
+		s.log.Error("Failed to process request SLAs", zap.Error(err))
+	}
+}
+
+// lead reports whether this replica is the leader, keeping the leadership lease it
+// holds or trying to take it
+func (s *Scheduler) lead(ctx context.Context) bool {
+	if s.lease != nil {
+		if err := s.lease.Check(); err == nil {
+			return true
+		}
+		s.log.Warn("Scheduler leadership lost", zap.String("instance_id", s.id))
+		s.resign()
+	}
+
+	lease, err := s.locker.Acquire(ctx, leaderKey)
+	if errors.Is(err, infrastructure.ErrLockHeld) {
+		return false
+	}
+	if err != nil {
+		s.log.Warn("Scheduler leader election failed", zap.Error(err))
+		return false
+	}
+
+	s.lease = lease
+	s.log.Info("Scheduler leadership acquired", zap.String("instance_id", s.id))
+	return true
+}
+
+// resign releases the leadership lease, if held
+func (s *Scheduler) resign() {
+	if s.lease == nil {
+		return
+	}
+	if err := s.lease.Release(context.Background()); err != nil {
+		s.log.Warn("Failed to release scheduler leadership", zap.Error(err))
+	}
+	s.lease = nil
+}
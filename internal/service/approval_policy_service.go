@@ -0,0 +1,116 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/domain/repository"
+	"github.com/senoagung27/warehousex/internal/dto"
+	"github.com/senoagung27/warehousex/internal/model"
+	"go.uber.org/zap"
+)
+
+var _ ApprovalPolicyServiceInterface = (*ApprovalPolicyService)(nil)
+
+type ApprovalPolicyService struct {
+	policyRepo repository.ApprovalPolicyRepository
+	auditRepo  repository.AuditLogRepository
+	log        *zap.Logger
+}
+
+func NewApprovalPolicyService(
+	policyRepo repository.ApprovalPolicyRepository,
+	auditRepo repository.AuditLogRepository,
+	log *zap.Logger,
+) *ApprovalPolicyService {
+	return &ApprovalPolicyService{
+		policyRepo: policyRepo,
+		auditRepo:  auditRepo,
+		log:        log,
+	}
+}
+
+func (s *ApprovalPolicyService) Create(input dto.CreateApprovalPolicyInput, userID uuid.UUID) (*model.ApprovalPolicy, error) {
+	policy := &model.ApprovalPolicy{
+		ID:           uuid.New(),
+		Name:         input.Name,
+		RequestType:  input.RequestType,
+		Category:     input.Category,
+		QuantityOver: input.QuantityOver,
+		ValueOver:    input.ValueOver,
+		Active:       true,
+		CreatedBy:    userID,
+	}
+	for i, role := range input.Steps {
+		policy.Steps = append(policy.Steps, model.ApprovalPolicyStep{
+			ID:       uuid.New(),
+			PolicyID: policy.ID,
+			StepNo:   i + 1,
+			Role:     role,
+		})
+	}
+
+	if err := s.policyRepo.Create(policy); err != nil {
+		return nil, fmt.Errorf("failed to create approval policy: %w", err)
+	}
+
+	afterJSON, _ := json.Marshal(policy)
+	_ = s.auditRepo.Create(&model.AuditLog{
+		ID:         uuid.New(),
+		Entity:     "approval_policy",
+		EntityID:   policy.ID,
+		Action:     "CREATE",
+		UserID:     userID,
+		AfterValue: afterJSON,
+	})
+
+	s.log.Info("Approval policy created",
+		zap.String("policy_id", policy.ID.String()),
+		zap.String("name", policy.Name),
+		zap.Int("steps", len(policy.Steps)),
+	)
+
+	return policy, nil
+}
+
+func (s *ApprovalPolicyService) GetAll(includeInactive bool) ([]model.ApprovalPolicy, error) {
+	return s.policyRepo.FindAll(!includeInactive)
+}
+
+// Deactivate stops a policy from applying to new requests. Requests created while it
+// was active keep the approval chain they were given.
+func (s *ApprovalPolicyService) Deactivate(id uuid.UUID, userID uuid.UUID) (*model.ApprovalPolicy, error) {
+	policy, err := s.policyRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("approval policy not found")
+	}
+	if !policy.Active {
+		return policy, nil
+	}
+
+	beforeJSON, _ := json.Marshal(policy)
+	policy.Active = false
+
+	if err := s.policyRepo.Update(policy); err != nil {
+		return nil, fmt.Errorf("failed to deactivate approval policy: %w", err)
+	}
+
+	afterJSON, _ := json.Marshal(policy)
+	_ = s.auditRepo.Create(&model.AuditLog{
+		ID:          uuid.New(),
+		Entity:      "approval_policy",
+		EntityID:    policy.ID,
+		Action:      "DEACTIVATE",
+		UserID:      userID,
+		BeforeValue: beforeJSON,
+		AfterValue:  afterJSON,
+	})
+
+	s.log.Info("Approval policy deactivated",
+		zap.String("policy_id", policy.ID.String()),
+	)
+
+	return policy, nil
+}
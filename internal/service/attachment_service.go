@@ -0,0 +1,220 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/config"
+	"github.com/senoagung27/warehousex/internal/domain/repository"
+	"github.com/senoagung27/warehousex/internal/infrastructure"
+	"github.com/senoagung27/warehousex/internal/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var _ AttachmentServiceInterface = (*AttachmentService)(nil)
+
+// sniffLength is how much of an upload is read to detect its content type
+const sniffLength = 512
+
+type AttachmentService struct {
+	attachmentRepo repository.AttachmentRepository
+	requestRepo    repository.RequestRepository
+	auditRepo      repository.AuditLogRepository
+	store          infrastructure.BlobStore
+	cfg            config.StorageConfig
+	db             *gorm.DB
+	log            *zap.Logger
+}
+
+func NewAttachmentService(
+	attachmentRepo repository.AttachmentRepository,
+	requestRepo repository.RequestRepository,
+	auditRepo repository.AuditLogRepository,
+	store infrastructure.BlobStore,
+	cfg config.StorageConfig,
+	db *gorm.DB,
+	log *zap.Logger,
+) *AttachmentService {
+	return &AttachmentService{
+		attachmentRepo: attachmentRepo,
+		requestRepo:    requestRepo,
+		auditRepo:      auditRepo,
+		store:          store,
+		cfg:            cfg,
+		db:             db,
+		log:            log,
+	}
+}
+
+// canUpload checks if the user may attach files: the request's creator, or a
+// supervisor or admin
+func canUpload(req *model.Request, userID uuid.UUID, userRole string) bool {
+	return req.CreatedBy == userID || model.CanApprove(userRole)
+}
+
+// canDownload checks if the user may read a request's files: whoever may upload
+// them, and auditors
+func canDownload(req *model.Request, userID uuid.UUID, userRole string) bool {
+	return canUpload(req, userID, userRole) || userRole == model.RoleAuditor
+}
+
+// Upload stores a file for a request. The content type is detected from the file
+// itself, not taken from the client, and must be one of the allowed types; the
+// SHA-256 of the content is kept with the attachment.
+func (s *AttachmentService) Upload(ctx context.Context, requestID uuid.UUID, userID uuid.UUID, userRole string, fileName string, size int64, body io.Reader) (*model.RequestAttachment, error) {
+	req, err := s.requestRepo.FindByID(requestID)
+	if err != nil {
+		return nil, errors.New("request not found")
+	}
+	if !canUpload(req, userID, userRole) {
+		return nil, errors.New("insufficient permissions to attach files to this request")
+	}
+
+	if size <= 0 {
+		return nil, errors.New("file is empty")
+	}
+	if size > s.cfg.MaxFileSize {
+		return nil, fmt.Errorf("file too large: limit is %d bytes", s.cfg.MaxFileSize)
+	}
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(body, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	head = head[:n]
+
+	contentType, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	if !s.allowedType(contentType) {
+		return nil, fmt.Errorf("unsupported file type: %s", contentType)
+	}
+
+	name := filepath.Base(strings.ReplaceAll(fileName, "\\", "/"))
+	if name == "." || name == "/" || name == "" {
+		name = "attachment"
+	}
+	name = strings.ToValidUTF8(name, "\uFFFD")
+	// Keep the tail, where the extension is, without splitting a character
+	if len(name) > 255 {
+		start := len(name) - 255
+		for !utf8.RuneStart(name[start]) {
+			start++
+		}
+		name = name[start:]
+	}
+
+	attachment := &model.RequestAttachment{
+		ID:          uuid.New(),
+		RequestID:   requestID,
+		FileName:    name,
+		ContentType: contentType,
+		Size:        size,
+		UploadedBy:  userID,
+	}
+	attachment.StorageKey = fmt.Sprintf("requests/%s/%s", requestID, attachment.ID)
+
+	hash := sha256.New()
+	content := io.TeeReader(io.MultiReader(bytes.NewReader(head), body), hash)
+	if err := s.store.Put(ctx, attachment.StorageKey, content, size, contentType); err != nil {
+		return nil, err
+	}
+	attachment.Checksum = hex.EncodeToString(hash.Sum(nil))
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.attachmentRepo.CreateWithTx(tx, attachment); err != nil {
+			return fmt.Errorf("failed to create attachment: %w", err)
+		}
+
+		afterJSON, _ := json.Marshal(attachment)
+		if err := s.auditRepo.CreateWithTx(tx, &model.AuditLog{
+			ID:         uuid.New(),
+			Entity:     "request",
+			EntityID:   requestID,
+			Action:     "ATTACHMENT_UPLOADED",
+			UserID:     userID,
+			AfterValue: afterJSON,
+		}); err != nil {
+			return fmt.Errorf("failed to create audit log: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		// The blob has no record; remove it rather than leave it orphaned
+		if delErr := s.store.Delete(ctx, attachment.StorageKey); delErr != nil {
+			s.log.Warn("Failed to remove orphaned attachment blob",
+				zap.String("key", attachment.StorageKey),
+				zap.Error(delErr),
+			)
+		}
+		return nil, err
+	}
+
+	s.log.Info("Attachment uploaded",
+		zap.String("request_id", requestID.String()),
+		zap.String("attachment_id", attachment.ID.String()),
+		zap.String("content_type", contentType),
+		zap.Int64("size", size),
+	)
+
+	return attachment, nil
+}
+
+func (s *AttachmentService) allowedType(contentType string) bool {
+	for _, allowed := range s.cfg.AllowedTypes {
+		if strings.TrimSpace(allowed) == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAll lists a request's attachments for a user allowed to read them
+func (s *AttachmentService) GetAll(requestID uuid.UUID, userID uuid.UUID, userRole string) ([]model.RequestAttachment, error) {
+	req, err := s.requestRepo.FindByID(requestID)
+	if err != nil {
+		return nil, errors.New("request not found")
+	}
+	if !canDownload(req, userID, userRole) {
+		return nil, errors.New("insufficient permissions to list attachments of this request")
+	}
+	return s.attachmentRepo.FindByRequest(requestID)
+}
+
+// Download opens an attachment's content for a user allowed to read it. The caller
+// closes the reader.
+func (s *AttachmentService) Download(ctx context.Context, requestID, attachmentID uuid.UUID, userID uuid.UUID, userRole string) (*model.RequestAttachment, io.ReadCloser, error) {
+	req, err := s.requestRepo.FindByID(requestID)
+	if err != nil {
+		return nil, nil, errors.New("request not found")
+	}
+	if !canDownload(req, userID, userRole) {
+		return nil, nil, errors.New("insufficient permissions to download attachments of this request")
+	}
+
+	attachment, err := s.attachmentRepo.FindByID(requestID, attachmentID)
+	if err != nil {
+		return nil, nil, errors.New("attachment not found")
+	}
+
+	content, err := s.store.Get(ctx, attachment.StorageKey)
+	if errors.Is(err, infrastructure.ErrBlobNotFound) {
+		return nil, nil, errors.New("attachment content not found")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	return attachment, content, nil
+}
@@ -1,6 +1,12 @@
 package service
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -10,93 +16,469 @@ import (
 	"github.com/senoagung27/warehousex/internal/config"
 	"github.com/senoagung27/warehousex/internal/domain/repository"
 	"github.com/senoagung27/warehousex/internal/dto"
+	"github.com/senoagung27/warehousex/internal/infrastructure"
 	"github.com/senoagung27/warehousex/internal/model"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 var _ AuthServiceInterface = (*AuthService)(nil)
 
 type AuthService struct {
-	userRepo repository.UserRepository
-	jwtCfg   config.JWTConfig
-	log      *zap.Logger
+	userRepo         repository.UserRepository
+	invitationRepo   repository.InvitationRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	auditRepo        repository.AuditLogRepository
+	redisClient      *infrastructure.RedisClient
+	keys             *infrastructure.KeySet
+	jwtCfg           config.JWTConfig
+	authCfg          config.AuthConfig
+	loginCfg         config.LoginThrottleConfig
+	db               *gorm.DB
+	log              *zap.Logger
 }
 
-func NewAuthService(userRepo repository.UserRepository, jwtCfg config.JWTConfig, log *zap.Logger) *AuthService {
+func NewAuthService(
+	userRepo repository.UserRepository,
+	invitationRepo repository.InvitationRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	auditRepo repository.AuditLogRepository,
+	redisClient *infrastructure.RedisClient,
+	keys *infrastructure.KeySet,
+	jwtCfg config.JWTConfig,
+	authCfg config.AuthConfig,
+	loginCfg config.LoginThrottleConfig,
+	db *gorm.DB,
+	log *zap.Logger,
+) *AuthService {
 	return &AuthService{
-		userRepo: userRepo,
-		jwtCfg:   jwtCfg,
-		log:      log,
+		userRepo:         userRepo,
+		invitationRepo:   invitationRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		auditRepo:        auditRepo,
+		redisClient:      redisClient,
+		keys:             keys,
+		jwtCfg:           jwtCfg,
+		authCfg:          authCfg,
+		loginCfg:         loginCfg,
+		db:               db,
+		log:              log,
 	}
 }
 
-func (s *AuthService) Register(input dto.RegisterInput) (*dto.AuthResponse, error) {
+// Register creates a staff account pending admin activation. It is refused unless
+// public registration is enabled; everyone else joins through an invitation.
+func (s *AuthService) Register(input dto.RegisterInput) (*model.User, error) {
+	if s.authCfg.Registration != config.RegistrationPending {
+		return nil, errors.New("public registration is disabled; ask an admin for an invitation")
+	}
+
 	existing, _ := s.userRepo.FindByEmail(input.Email)
 	if existing != nil {
 		return nil, errors.New("email already registered")
 	}
 
-	hash, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	user, err := newUser(input.Name, input.Email, input.Password, model.RoleStaff, model.UserStatusPending)
 	if err != nil {
-		return nil, fmt.Errorf("failed to hash password: %w", err)
-	}
-
-	user := &model.User{
-		ID:           uuid.New(),
-		Name:         input.Name,
-		Email:        input.Email,
-		PasswordHash: string(hash),
-		Role:         input.Role,
+		return nil, err
 	}
 
 	if err := s.userRepo.Create(user); err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	token, err := s.generateToken(user)
+	afterJSON, _ := json.Marshal(user)
+	_ = s.auditRepo.Create(&model.AuditLog{
+		ID:         uuid.New(),
+		Entity:     "user",
+		EntityID:   user.ID,
+		Action:     "REGISTER",
+		UserID:     user.ID,
+		AfterValue: afterJSON,
+	})
+
+	s.log.Info("User registered pending activation", zap.String("email", user.Email))
+
+	return user, nil
+}
+
+// AcceptInvitation creates the invited account with the invitation's email and role
+// and logs it in. Each invitation can be accepted once.
+func (s *AuthService) AcceptInvitation(input dto.AcceptInvitationInput) (*dto.AuthResponse, error) {
+	invitation, err := s.invitationRepo.FindByTokenHash(hashToken(input.Token))
+	if err != nil || !invitation.Usable(time.Now()) {
+		return nil, errors.New("invalid or expired invitation")
+	}
+
+	existing, _ := s.userRepo.FindByEmail(invitation.Email)
+	if existing != nil {
+		return nil, errors.New("email already registered")
+	}
+
+	user, err := newUser(input.Name, invitation.Email, input.Password, invitation.Role, model.UserStatusActive)
 	if err != nil {
 		return nil, err
 	}
 
-	s.log.Info("User registered", zap.String("email", user.Email), zap.String("role", user.Role))
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.userRepo.CreateWithTx(tx, user); err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		if err := s.invitationRepo.AcceptWithTx(tx, invitation.ID, user.ID, time.Now()); err != nil {
+			return errors.New("invalid or expired invitation")
+		}
 
-	return &dto.AuthResponse{Token: token, User: *user}, nil
+		afterJSON, _ := json.Marshal(user)
+		return s.auditRepo.CreateWithTx(tx, &model.AuditLog{
+			ID:         uuid.New(),
+			Entity:     "invitation",
+			EntityID:   invitation.ID,
+			Action:     "ACCEPT",
+			UserID:     user.ID,
+			AfterValue: afterJSON,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.issueTokens(s.db, user, uuid.New())
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Invitation accepted",
+		zap.String("invitation_id", invitation.ID.String()),
+		zap.String("email", user.Email),
+		zap.String("role", user.Role),
+	)
+
+	return response, nil
 }
 
-func (s *AuthService) Login(input dto.LoginInput) (*dto.AuthResponse, error) {
-	user, err := s.userRepo.FindByEmail(input.Email)
+// BootstrapAdmin creates the first admin. It refuses once any admin exists, so it
+// cannot be used to add admins later.
+func (s *AuthService) BootstrapAdmin(input dto.BootstrapAdminInput) (*model.User, error) {
+	admins, err := s.userRepo.FindByRole(model.RoleAdmin)
 	if err != nil {
-		return nil, errors.New("invalid email or password")
+		return nil, fmt.Errorf("failed to look up admins: %w", err)
+	}
+	if len(admins) > 0 {
+		return nil, errors.New("an admin already exists; invite further admins through the API")
+	}
+
+	existing, _ := s.userRepo.FindByEmail(input.Email)
+	if existing != nil {
+		return nil, errors.New("email already registered")
+	}
+
+	user, err := newUser(input.Name, input.Email, input.Password, model.RoleAdmin, model.UserStatusActive)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	afterJSON, _ := json.Marshal(user)
+	_ = s.auditRepo.Create(&model.AuditLog{
+		ID:         uuid.New(),
+		Entity:     "user",
+		EntityID:   user.ID,
+		Action:     "BOOTSTRAP",
+		UserID:     model.SystemUserID,
+		AfterValue: afterJSON,
+	})
+
+	s.log.Info("First admin created", zap.String("email", user.Email))
+
+	return user, nil
+}
+
+// ResetPassword sets a new password with the token from an admin-forced reset,
+// lifts the block and login backoff on the account and ends its sessions
+func (s *AuthService) ResetPassword(ctx context.Context, input dto.ResetPasswordInput) (*model.User, error) {
+	user, err := s.userRepo.FindByResetTokenHash(hashToken(input.Token))
+	if err != nil || user.ResetTokenExpiresAt == nil || !time.Now().Before(*user.ResetTokenExpiresAt) {
+		return nil, errors.New("invalid or expired reset token")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	beforeJSON, _ := json.Marshal(user)
+	user.PasswordHash = string(hash)
+	user.PasswordResetRequired = false
+	user.ResetTokenHash = nil
+	user.ResetTokenExpiresAt = nil
+	user.LockedUntil = nil
+
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to reset password: %w", err)
+	}
+
+	afterJSON, _ := json.Marshal(user)
+	_ = s.auditRepo.Create(&model.AuditLog{
+		ID:          uuid.New(),
+		Entity:      "user",
+		EntityID:    user.ID,
+		Action:      "PASSWORD_RESET",
+		UserID:      user.ID,
+		BeforeValue: beforeJSON,
+		AfterValue:  afterJSON,
+	})
+
+	if err := s.RevokeSessions(ctx, user.ID); err != nil {
+		s.log.Error("Failed to revoke sessions after password reset", zap.String("user_id", user.ID.String()), zap.Error(err))
+	}
+	if err := s.ResetLoginFailures(ctx, user.Email); err != nil {
+		s.log.Error("Failed to clear login failures", zap.String("user_id", user.ID.String()), zap.Error(err))
+	}
+
+	s.log.Info("Password reset", zap.String("user_id", user.ID.String()))
+
+	return user, nil
+}
+
+// Login checks the password, refusing with a LoginThrottledError while the account
+// or client IP is backing off after failed attempts or the account is locked
+func (s *AuthService) Login(ctx context.Context, input dto.LoginInput, clientIP string) (*dto.AuthResponse, error) {
+	user, _ := s.userRepo.FindByEmail(input.Email)
+
+	if err := s.checkLoginThrottle(ctx, user, input.Email, clientIP); err != nil {
+		return nil, err
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(input.Password)); err != nil {
+	if user == nil || bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(input.Password)) != nil {
+		s.loginFailed(ctx, user, input.Email, clientIP)
 		return nil, errors.New("invalid email or password")
 	}
 
-	token, err := s.generateToken(user)
+	if err := user.CheckAccount(); err != nil {
+		return nil, err
+	}
+
+	if err := s.ResetLoginFailures(ctx, user.Email); err != nil {
+		s.log.Error("Failed to clear login failures", zap.String("user_id", user.ID.String()), zap.Error(err))
+	}
+
+	response, err := s.issueTokens(s.db, user, uuid.New())
 	if err != nil {
 		return nil, err
 	}
 
 	s.log.Info("User logged in", zap.String("email", user.Email))
 
-	return &dto.AuthResponse{Token: token, User: *user}, nil
+	return response, nil
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token. A token
+// that was already exchanged or revoked means it leaked, so the whole family it
+// belongs to is revoked, including access tokens still in use.
+func (s *AuthService) Refresh(ctx context.Context, input dto.RefreshInput) (*dto.AuthResponse, error) {
+	current, err := s.refreshTokenRepo.FindByTokenHash(hashToken(input.RefreshToken))
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+	if current.UsedAt != nil || current.RevokedAt != nil {
+		s.reuseDetected(ctx, current)
+		return nil, errors.New("invalid refresh token")
+	}
+	if !time.Now().Before(current.ExpiresAt) {
+		return nil, errors.New("refresh token expired")
+	}
+
+	user, err := s.userRepo.FindByID(current.UserID)
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+	if err := user.CheckAccount(); err != nil {
+		return nil, err
+	}
+
+	var response *dto.AuthResponse
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.refreshTokenRepo.MarkUsedWithTx(tx, current.ID, time.Now()); err != nil {
+			return err
+		}
+		response, err = s.issueTokens(tx, user, current.FamilyID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrRefreshTokenUsed) {
+			// Another request exchanged the token first
+			s.reuseDetected(ctx, current)
+			return nil, errors.New("invalid refresh token")
+		}
+		return nil, err
+	}
+
+	return response, nil
 }
 
-func (s *AuthService) generateToken(user *model.User) (string, error) {
+// Logout revokes the access token used and its session's refresh tokens, or the
+// session of the given refresh token
+func (s *AuthService) Logout(ctx context.Context, userID uuid.UUID, jti string, expiresAt time.Time, input dto.LogoutInput) error {
+	if err := s.redisClient.RevokeToken(ctx, jti, time.Until(expiresAt)); err != nil {
+		return err
+	}
+
+	var session *model.RefreshToken
+	var err error
+	if input.RefreshToken != "" {
+		session, err = s.refreshTokenRepo.FindByTokenHash(hashToken(input.RefreshToken))
+	} else {
+		session, err = s.refreshTokenRepo.FindByAccessJTI(jti)
+	}
+	if err != nil || session.UserID != userID {
+		// The access token is revoked; there is no session of the caller's to end
+		return nil
+	}
+
+	tokens, err := s.refreshTokenRepo.RevokeFamily(session.FamilyID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	s.revokeAccessTokens(ctx, tokens)
+
+	s.log.Info("User logged out", zap.String("user_id", userID.String()))
+
+	return nil
+}
+
+// RevokeSessions ends every session of the user, e.g. after a password change
+func (s *AuthService) RevokeSessions(ctx context.Context, userID uuid.UUID) error {
+	tokens, err := s.refreshTokenRepo.RevokeByUser(userID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	s.revokeAccessTokens(ctx, tokens)
+	return nil
+}
+
+// JWKS returns the public keys tokens can be verified with
+func (s *AuthService) JWKS() infrastructure.JWKS {
+	return s.keys.JWKS()
+}
+
+// issueTokens signs an access token and creates the refresh token issued with it
+// in the given family
+func (s *AuthService) issueTokens(tx *gorm.DB, user *model.User, familyID uuid.UUID) (*dto.AuthResponse, error) {
+	now := time.Now()
+	jti := uuid.New().String()
+	accessExpiresAt := now.Add(s.jwtCfg.AccessTTL)
+
 	claims := jwt.MapClaims{
+		"jti":     jti,
 		"user_id": user.ID.String(),
 		"role":    user.Role,
-		"exp":     time.Now().Add(time.Duration(s.jwtCfg.ExpirationHours) * time.Hour).Unix(),
-		"iat":     time.Now().Unix(),
+		"exp":     accessExpiresAt.Unix(),
+		"iat":     now.Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(s.jwtCfg.Secret))
+	accessToken, err := s.keys.Sign(claims)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate token: %w", err)
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	refreshToken, refreshHash, err := newToken()
+	if err != nil {
+		return nil, err
 	}
 
-	return tokenString, nil
+	if err := s.refreshTokenRepo.CreateWithTx(tx, &model.RefreshToken{
+		ID:              uuid.New(),
+		UserID:          user.ID,
+		FamilyID:        familyID,
+		TokenHash:       refreshHash,
+		AccessJTI:       jti,
+		AccessExpiresAt: accessExpiresAt,
+		ExpiresAt:       now.Add(s.jwtCfg.RefreshTTL),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	return &dto.AuthResponse{
+		Token:        accessToken,
+		ExpiresAt:    accessExpiresAt,
+		RefreshToken: refreshToken,
+		User:         *user,
+	}, nil
+}
+
+// reuseDetected revokes the family of a refresh token presented after it was
+// exchanged or revoked, and audits the attempt
+func (s *AuthService) reuseDetected(ctx context.Context, token *model.RefreshToken) {
+	tokens, err := s.refreshTokenRepo.RevokeFamily(token.FamilyID, time.Now())
+	if err != nil {
+		s.log.Error("Failed to revoke refresh token family",
+			zap.String("family_id", token.FamilyID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+	s.revokeAccessTokens(ctx, tokens)
+
+	afterJSON, _ := json.Marshal(map[string]interface{}{
+		"family_id":     token.FamilyID,
+		"refresh_token": token.ID,
+	})
+	_ = s.auditRepo.Create(&model.AuditLog{
+		ID:         uuid.New(),
+		Entity:     "user",
+		EntityID:   token.UserID,
+		Action:     "REFRESH_TOKEN_REUSE",
+		UserID:     token.UserID,
+		AfterValue: afterJSON,
+	})
+
+	s.log.Warn("Refresh token reuse detected; session revoked",
+		zap.String("user_id", token.UserID.String()),
+		zap.String("family_id", token.FamilyID.String()),
+	)
+}
+
+// revokeAccessTokens puts the access tokens issued with the refresh tokens on the
+// revocation list
+func (s *AuthService) revokeAccessTokens(ctx context.Context, tokens []model.RefreshToken) {
+	for _, token := range tokens {
+		if err := s.redisClient.RevokeToken(ctx, token.AccessJTI, time.Until(token.AccessExpiresAt)); err != nil {
+			s.log.Error("Failed to revoke access token", zap.String("jti", token.AccessJTI), zap.Error(err))
+		}
+	}
+}
+
+func newUser(name, email, password, role, status string) (*model.User, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return &model.User{
+		ID:           uuid.New(),
+		Name:         name,
+		Email:        email,
+		PasswordHash: string(hash),
+		Role:         role,
+		Status:       status,
+	}, nil
+}
+
+// newToken returns a random URL-safe token and the hash to store for it
+func newToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/domain/repository"
+	"github.com/senoagung27/warehousex/internal/dto"
+	"github.com/senoagung27/warehousex/internal/model"
+	"go.uber.org/zap"
+)
+
+var _ DelegationServiceInterface = (*DelegationService)(nil)
+
+type DelegationService struct {
+	delegationRepo repository.DelegationRepository
+	userRepo       repository.UserRepository
+	auditRepo      repository.AuditLogRepository
+	log            *zap.Logger
+}
+
+func NewDelegationService(
+	delegationRepo repository.DelegationRepository,
+	userRepo repository.UserRepository,
+	auditRepo repository.AuditLogRepository,
+	log *zap.Logger,
+) *DelegationService {
+	return &DelegationService{
+		delegationRepo: delegationRepo,
+		userRepo:       userRepo,
+		auditRepo:      auditRepo,
+		log:            log,
+	}
+}
+
+// Create delegates the caller's approval authority. The delegate signs with the
+// caller's role while the delegation is active.
+func (s *DelegationService) Create(input dto.CreateDelegationInput, userID uuid.UUID, userRole string) (*model.ApprovalDelegation, error) {
+	if !model.CanApprove(userRole) {
+		return nil, errors.New("insufficient permissions to delegate approvals")
+	}
+
+	delegateID, err := uuid.Parse(input.DelegateID)
+	if err != nil {
+		return nil, errors.New("invalid delegate ID")
+	}
+	if delegateID == userID {
+		return nil, errors.New("cannot delegate approvals to yourself")
+	}
+	delegate, err := s.userRepo.FindByID(delegateID)
+	if err != nil {
+		return nil, errors.New("delegate not found")
+	}
+	if delegate.Role == model.RoleAuditor {
+		return nil, errors.New("auditors cannot be approval delegates")
+	}
+	if !input.EndsAt.After(time.Now()) {
+		return nil, errors.New("ends_at must be in the future")
+	}
+
+	delegation := &model.ApprovalDelegation{
+		ID:           uuid.New(),
+		DelegatorID:  userID,
+		DelegateID:   delegateID,
+		RequestTypes: model.StringList(input.RequestTypes),
+		StartsAt:     input.StartsAt,
+		EndsAt:       input.EndsAt,
+		Reason:       input.Reason,
+	}
+
+	if err := s.delegationRepo.Create(delegation); err != nil {
+		return nil, fmt.Errorf("failed to create delegation: %w", err)
+	}
+
+	afterJSON, _ := json.Marshal(delegation)
+	_ = s.auditRepo.Create(&model.AuditLog{
+		ID:         uuid.New(),
+		Entity:     "delegation",
+		EntityID:   delegation.ID,
+		Action:     "CREATE",
+		UserID:     userID,
+		AfterValue: afterJSON,
+	})
+
+	s.log.Info("Approval delegation created",
+		zap.String("delegation_id", delegation.ID.String()),
+		zap.String("delegator_id", userID.String()),
+		zap.String("delegate_id", delegateID.String()),
+	)
+
+	return delegation, nil
+}
+
+// GetAll returns the delegations the user gave or received; admins see every delegation
+func (s *DelegationService) GetAll(userID uuid.UUID, userRole string) ([]model.ApprovalDelegation, error) {
+	if userRole == model.RoleAdmin {
+		return s.delegationRepo.FindByUser(nil)
+	}
+	return s.delegationRepo.FindByUser(&userID)
+}
+
+// Revoke ends a delegation early. Only its delegator or an admin may revoke it.
+func (s *DelegationService) Revoke(id uuid.UUID, userID uuid.UUID, userRole string) (*model.ApprovalDelegation, error) {
+	delegation, err := s.delegationRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("delegation not found")
+	}
+	if delegation.DelegatorID != userID && userRole != model.RoleAdmin {
+		return nil, errors.New("insufficient permissions to revoke this delegation")
+	}
+	if delegation.RevokedAt != nil {
+		return delegation, nil
+	}
+
+	beforeJSON, _ := json.Marshal(delegation)
+	now := time.Now()
+	delegation.RevokedAt = &now
+
+	if err := s.delegationRepo.Update(delegation); err != nil {
+		return nil, fmt.Errorf("failed to revoke delegation: %w", err)
+	}
+
+	afterJSON, _ := json.Marshal(delegation)
+	_ = s.auditRepo.Create(&model.AuditLog{
+		ID:          uuid.New(),
+		Entity:      "delegation",
+		EntityID:    delegation.ID,
+		Action:      "REVOKE",
+		UserID:      userID,
+		BeforeValue: beforeJSON,
+		AfterValue:  afterJSON,
+	})
+
+	s.log.Info("Approval delegation revoked",
+		zap.String("delegation_id", delegation.ID.String()),
+	)
+
+	return delegation, nil
+}
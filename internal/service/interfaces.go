@@ -1,35 +1,108 @@
 package service
 
 import (
+	"context"
+	"io"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/senoagung27/warehousex/internal/dto"
+	"github.com/senoagung27/warehousex/internal/infrastructure"
 	"github.com/senoagung27/warehousex/internal/model"
 )
 
 // AuthServiceInterface defines the contract for authentication operations
 type AuthServiceInterface interface {
-	Register(input dto.RegisterInput) (*dto.AuthResponse, error)
-	Login(input dto.LoginInput) (*dto.AuthResponse, error)
+	Register(input dto.RegisterInput) (*model.User, error)
+	AcceptInvitation(input dto.AcceptInvitationInput) (*dto.AuthResponse, error)
+	BootstrapAdmin(input dto.BootstrapAdminInput) (*model.User, error)
+	ResetPassword(ctx context.Context, input dto.ResetPasswordInput) (*model.User, error)
+	Login(ctx context.Context, input dto.LoginInput, clientIP string) (*dto.AuthResponse, error)
+	Refresh(ctx context.Context, input dto.RefreshInput) (*dto.AuthResponse, error)
+	Logout(ctx context.Context, userID uuid.UUID, jti string, expiresAt time.Time, input dto.LogoutInput) error
+	RevokeSessions(ctx context.Context, userID uuid.UUID) error
+	ResetLoginFailures(ctx context.Context, email string) error
+	JWKS() infrastructure.JWKS
+}
+
+// InvitationServiceInterface defines the contract for invitation operations
+type InvitationServiceInterface interface {
+	Create(input dto.CreateInvitationInput, userID uuid.UUID) (*dto.InvitationResponse, error)
+	GetAll(pendingOnly bool) ([]model.Invitation, error)
+	Revoke(id uuid.UUID, userID uuid.UUID) (*model.Invitation, error)
+}
+
+// UserServiceInterface defines the contract for user administration
+type UserServiceInterface interface {
+	GetAll(page, limit int, search, role, status string) ([]model.User, int64, error)
+	GetByID(id uuid.UUID) (*model.User, error)
+	UpdateRole(id uuid.UUID, input dto.UpdateUserRoleInput, userID uuid.UUID) (*model.User, error)
+	Activate(id uuid.UUID, userID uuid.UUID) (*model.User, error)
+	Deactivate(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*model.User, error)
+	Unlock(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*model.User, error)
+	ForcePasswordReset(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dto.PasswordResetResponse, error)
 }
 
 // InventoryServiceInterface defines the contract for inventory operations
 type InventoryServiceInterface interface {
 	Create(input dto.CreateInventoryInput, userID uuid.UUID) (*model.Inventory, error)
-	GetByID(id uuid.UUID) (*model.Inventory, error)
-	GetAll(page, limit int) ([]model.Inventory, int64, error)
+	GetByID(id uuid.UUID, asOf *time.Time) (*model.Inventory, error)
+	GetAll(page, limit int, asOf *time.Time) ([]model.Inventory, int64, error)
+	GetBalances(asOf *time.Time) ([]model.Inventory, error)
 	Update(id uuid.UUID, input dto.UpdateInventoryInput, userID uuid.UUID) (*model.Inventory, error)
+	GetBins(id uuid.UUID, warehouseID *uuid.UUID) ([]model.BinStock, error)
+	GetExpiringLots(days int, warehouseID *uuid.UUID) ([]model.Lot, error)
+	GetSerial(serial string, itemID *uuid.UUID) (*model.SerialNumber, error)
+	GetMovements(id uuid.UUID, warehouseID *uuid.UUID, from, to *time.Time, page, limit int) ([]model.StockMovement, int64, error)
+}
+
+// WarehouseServiceInterface defines the contract for warehouse operations
+type WarehouseServiceInterface interface {
+	Create(input dto.CreateWarehouseInput, userID uuid.UUID) (*model.Warehouse, error)
+	GetByID(id uuid.UUID) (*model.Warehouse, error)
+	GetAll(page, limit int) ([]model.Warehouse, int64, error)
+	Update(id uuid.UUID, input dto.UpdateWarehouseInput, userID uuid.UUID) (*model.Warehouse, error)
+	CreateLocation(warehouseID uuid.UUID, input dto.CreateLocationInput, userID uuid.UUID) (*model.Location, error)
+	GetLocations(warehouseID uuid.UUID, locationType string) ([]model.Location, error)
 }
 
 // RequestServiceInterface defines the contract for request operations
 type RequestServiceInterface interface {
 	CreateInbound(input dto.CreateRequestInput, userID uuid.UUID) (*model.Request, error)
-	CreateOutbound(input dto.CreateRequestInput, userID uuid.UUID) (*model.Request, error)
-	ApproveRequest(requestID uuid.UUID, approverID uuid.UUID, approverRole string) (*model.Request, error)
-	RejectRequest(requestID uuid.UUID, approverID uuid.UUID, approverRole string) (*model.Request, error)
+	CreateOutbound(ctx context.Context, input dto.CreateRequestInput, userID uuid.UUID) (*model.Request, error)
+	CreateTransfer(ctx context.Context, input dto.CreateTransferInput, userID uuid.UUID) (*model.Request, error)
+	ApproveRequest(ctx context.Context, requestID uuid.UUID, approverID uuid.UUID, approverRole string, input dto.ApproveRequestInput) (*model.Request, error)
+	RejectRequest(ctx context.Context, requestID uuid.UUID, approverID uuid.UUID, approverRole string, input dto.RejectRequestInput) (*model.Request, error)
+	BulkDecide(ctx context.Context, input dto.BulkDecisionInput, approverID uuid.UUID, approverRole string) []dto.BulkDecisionResult
+	CancelRequest(ctx context.Context, requestID uuid.UUID, userID uuid.UUID, userRole string, input dto.CancelRequestInput) (*model.Request, error)
+	ShipRequest(ctx context.Context, requestID uuid.UUID, userID uuid.UUID, input dto.CreateShipmentInput) (*model.Request, error)
+	AddComment(requestID uuid.UUID, userID uuid.UUID, userRole string, input dto.CreateCommentInput) (*model.RequestComment, error)
+	GetComments(requestID uuid.UUID) ([]model.RequestComment, error)
 	GetByID(id uuid.UUID) (*model.Request, error)
 	GetAll(page, limit int, reqType, status string) ([]model.Request, int64, error)
 }
 
+// AttachmentServiceInterface defines the contract for request attachment operations
+type AttachmentServiceInterface interface {
+	Upload(ctx context.Context, requestID uuid.UUID, userID uuid.UUID, userRole string, fileName string, size int64, body io.Reader) (*model.RequestAttachment, error)
+	GetAll(requestID uuid.UUID, userID uuid.UUID, userRole string) ([]model.RequestAttachment, error)
+	Download(ctx context.Context, requestID, attachmentID uuid.UUID, userID uuid.UUID, userRole string) (*model.RequestAttachment, io.ReadCloser, error)
+}
+
+// ApprovalPolicyServiceInterface defines the contract for approval policy operations
+type ApprovalPolicyServiceInterface interface {
+	Create(input dto.CreateApprovalPolicyInput, userID uuid.UUID) (*model.ApprovalPolicy, error)
+	GetAll(includeInactive bool) ([]model.ApprovalPolicy, error)
+	Deactivate(id uuid.UUID, userID uuid.UUID) (*model.ApprovalPolicy, error)
+}
+
+// DelegationServiceInterface defines the contract for approval delegation operations
+type DelegationServiceInterface interface {
+	Create(input dto.CreateDelegationInput, userID uuid.UUID, userRole string) (*model.ApprovalDelegation, error)
+	GetAll(userID uuid.UUID, userRole string) ([]model.ApprovalDelegation, error)
+	Revoke(id uuid.UUID, userID uuid.UUID, userRole string) (*model.ApprovalDelegation, error)
+}
+
 // AuditServiceInterface defines the contract for audit log operations
 type AuditServiceInterface interface {
 	GetAll(page, limit int, entityName string, entityID *uuid.UUID) ([]model.AuditLog, int64, error)
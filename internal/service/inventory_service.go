@@ -2,7 +2,9 @@ package service
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/senoagung27/warehousex/internal/domain/repository"
@@ -15,17 +17,32 @@ var _ InventoryServiceInterface = (*InventoryService)(nil)
 
 type InventoryService struct {
 	inventoryRepo repository.InventoryRepository
+	stockRepo     repository.StockRepository
+	lotRepo       repository.LotRepository
+	serialRepo    repository.SerialRepository
+	movementRepo  repository.MovementRepository
+	warehouseRepo repository.WarehouseRepository
 	auditRepo     repository.AuditLogRepository
 	log           *zap.Logger
 }
 
 func NewInventoryService(
 	inventoryRepo repository.InventoryRepository,
+	stockRepo repository.StockRepository,
+	lotRepo repository.LotRepository,
+	serialRepo repository.SerialRepository,
+	movementRepo repository.MovementRepository,
+	warehouseRepo repository.WarehouseRepository,
 	auditRepo repository.AuditLogRepository,
 	log *zap.Logger,
 ) *InventoryService {
 	return &InventoryService{
 		inventoryRepo: inventoryRepo,
+		stockRepo:     stockRepo,
+		lotRepo:       lotRepo,
+		serialRepo:    serialRepo,
+		movementRepo:  movementRepo,
+		warehouseRepo: warehouseRepo,
 		auditRepo:     auditRepo,
 		log:           log,
 	}
@@ -33,12 +50,52 @@ func NewInventoryService(
 
 func (s *InventoryService) Create(input dto.CreateInventoryInput, userID uuid.UUID) (*model.Inventory, error) {
 	item := &model.Inventory{
-		ID:       uuid.New(),
-		ItemName: input.ItemName,
-		SKU:      input.SKU,
-		Quantity: input.Quantity,
-		Unit:     input.Unit,
-		Version:  1,
+		ID:         uuid.New(),
+		ItemName:   input.ItemName,
+		SKU:        input.SKU,
+		Unit:       input.Unit,
+		Category:   input.Category,
+		UnitValue:  input.UnitValue,
+		Serialized: input.Serialized,
+		Version:    1,
+	}
+
+	if input.Serialized && input.Quantity > 0 {
+		return nil, errors.New("serialized items cannot have opening stock; receive them with serial numbers")
+	}
+
+	// Opening stock is booked to a single warehouse, created together with the item
+	if input.Quantity > 0 {
+		if input.WarehouseID == "" {
+			return nil, errors.New("warehouse_id is required when quantity is greater than zero")
+		}
+		warehouseID, err := uuid.Parse(input.WarehouseID)
+		if err != nil {
+			return nil, errors.New("invalid warehouse ID")
+		}
+		if _, err := s.warehouseRepo.FindByID(warehouseID); err != nil {
+			return nil, errors.New("warehouse not found")
+		}
+		stockID := uuid.New()
+		item.Stocks = []model.InventoryStock{{
+			ID:          stockID,
+			ItemID:      item.ID,
+			WarehouseID: warehouseID,
+			Quantity:    input.Quantity,
+			Available:   input.Quantity,
+			Version:     1,
+			Movements: []model.StockMovement{{
+				ID:           uuid.New(),
+				StockID:      stockID,
+				ItemID:       item.ID,
+				WarehouseID:  warehouseID,
+				Type:         model.MovementTypeOpening,
+				Delta:        input.Quantity,
+				BalanceAfter: input.Quantity,
+				UserID:       &userID,
+			}},
+		}}
+		item.SumStocks()
 	}
 
 	if err := s.inventoryRepo.Create(item); err != nil {
@@ -63,18 +120,88 @@ func (s *InventoryService) Create(input dto.CreateInventoryInput, userID uuid.UU
 	return item, nil
 }
 
-func (s *InventoryService) GetByID(id uuid.UUID) (*model.Inventory, error) {
-	return s.inventoryRepo.FindByID(id)
+// GetByID returns an item with its current balances, or with the balances it had
+// just before asOf when set
+func (s *InventoryService) GetByID(id uuid.UUID, asOf *time.Time) (*model.Inventory, error) {
+	item, err := s.inventoryRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if asOf == nil {
+		return item, nil
+	}
+
+	if !item.CreatedAt.Before(*asOf) {
+		return nil, errors.New("inventory item not found at as_of")
+	}
+	items := []model.Inventory{*item}
+	if err := s.rebuildAsOf(items, []uuid.UUID{id}, *asOf); err != nil {
+		return nil, err
+	}
+	return &items[0], nil
 }
 
-func (s *InventoryService) GetAll(page, limit int) ([]model.Inventory, int64, error) {
+func (s *InventoryService) GetAll(page, limit int, asOf *time.Time) ([]model.Inventory, int64, error) {
 	if page <= 0 {
 		page = 1
 	}
 	if limit <= 0 || limit > 100 {
 		limit = 20
 	}
-	return s.inventoryRepo.FindAll(page, limit)
+
+	items, total, err := s.inventoryRepo.FindAll(page, limit, asOf)
+	if err != nil || asOf == nil {
+		return items, total, err
+	}
+	itemIDs := make([]uuid.UUID, len(items))
+	for i := range items {
+		itemIDs[i] = items[i].ID
+	}
+	if err := s.rebuildAsOf(items, itemIDs, *asOf); err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// GetBalances returns every item with its per-warehouse balances, current or as of
+// the given time, for bulk export
+func (s *InventoryService) GetBalances(asOf *time.Time) ([]model.Inventory, error) {
+	items, err := s.inventoryRepo.FindAllWithStocks(asOf)
+	if err != nil || asOf == nil {
+		return items, err
+	}
+	if err := s.rebuildAsOf(items, nil, *asOf); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// rebuildAsOf replaces the balances of the given items with the ones recorded in the
+// movement ledger just before asOf; a nil itemIDs reads the ledger for every item.
+// Reservations, bin and lot breakdowns only exist for the present and are dropped.
+func (s *InventoryService) rebuildAsOf(items []model.Inventory, itemIDs []uuid.UUID, asOf time.Time) error {
+	balances, err := s.movementRepo.BalancesAsOf(itemIDs, asOf)
+	if err != nil {
+		return fmt.Errorf("failed to rebuild balances: %w", err)
+	}
+
+	for i := range items {
+		item := &items[i]
+		stocks := make([]model.InventoryStock, 0, len(item.Stocks))
+		for _, stock := range item.Stocks {
+			quantity, ok := balances[stock.ID]
+			if !ok {
+				continue
+			}
+			stock.Quantity, stock.Reserved, stock.Available = quantity, 0, quantity
+			stocks = append(stocks, stock)
+		}
+		item.Stocks = stocks
+		item.SumStocks()
+		item.Bins = nil
+		item.Lots = nil
+	}
+	return nil
 }
 
 func (s *InventoryService) Update(id uuid.UUID, input dto.UpdateInventoryInput, userID uuid.UUID) (*model.Inventory, error) {
@@ -94,6 +221,18 @@ func (s *InventoryService) Update(id uuid.UUID, input dto.UpdateInventoryInput,
 	if input.Unit != "" {
 		item.Unit = input.Unit
 	}
+	if input.Category != "" {
+		item.Category = input.Category
+	}
+	if input.UnitValue != nil {
+		item.UnitValue = *input.UnitValue
+	}
+	if input.Serialized != nil && *input.Serialized != item.Serialized {
+		if item.Quantity > 0 {
+			return nil, errors.New("cannot change serialized flag while the item has stock")
+		}
+		item.Serialized = *input.Serialized
+	}
 
 	if err := s.inventoryRepo.Update(item); err != nil {
 		return nil, fmt.Errorf("failed to update inventory item: %w", err)
@@ -116,3 +255,57 @@ func (s *InventoryService) Update(id uuid.UUID, input dto.UpdateInventoryInput,
 
 	return item, nil
 }
+
+// GetBins returns the bin-level quantities of an item, ordered by location path for picking
+func (s *InventoryService) GetBins(id uuid.UUID, warehouseID *uuid.UUID) ([]model.BinStock, error) {
+	if _, err := s.inventoryRepo.FindByID(id); err != nil {
+		return nil, errors.New("inventory item not found")
+	}
+	return s.stockRepo.FindBinsByItem(id, warehouseID)
+}
+
+// GetExpiringLots lists lots with stock left that expire within the given number of days,
+// including lots that have already expired
+func (s *InventoryService) GetExpiringLots(days int, warehouseID *uuid.UUID) ([]model.Lot, error) {
+	if days < 0 {
+		return nil, errors.New("days cannot be negative")
+	}
+	return s.lotRepo.FindExpiring(time.Now().AddDate(0, 0, days), warehouseID)
+}
+
+// GetSerial returns one serialized unit with its current status and movement history.
+// The item ID is only needed when the same serial number exists for several items.
+func (s *InventoryService) GetSerial(serial string, itemID *uuid.UUID) (*model.SerialNumber, error) {
+	units, err := s.serialRepo.FindBySerial(serial, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up serial number: %w", err)
+	}
+
+	switch len(units) {
+	case 0:
+		return nil, errors.New("serial number not found")
+	case 1:
+		return &units[0], nil
+	default:
+		return nil, errors.New("serial number matches several items; pass item_id")
+	}
+}
+
+// GetMovements returns the stock movement ledger of an item, newest first, optionally
+// narrowed to one warehouse and a [from, to) time range
+func (s *InventoryService) GetMovements(id uuid.UUID, warehouseID *uuid.UUID, from, to *time.Time, page, limit int) ([]model.StockMovement, int64, error) {
+	if _, err := s.inventoryRepo.FindByID(id); err != nil {
+		return nil, 0, errors.New("inventory item not found")
+	}
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, 0, errors.New("invalid date range: from must be before to")
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	return s.movementRepo.FindByItem(page, limit, id, warehouseID, from, to)
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/config"
+	"github.com/senoagung27/warehousex/internal/domain/repository"
+	"github.com/senoagung27/warehousex/internal/dto"
+	"github.com/senoagung27/warehousex/internal/model"
+	"go.uber.org/zap"
+)
+
+var _ InvitationServiceInterface = (*InvitationService)(nil)
+
+type InvitationService struct {
+	invitationRepo repository.InvitationRepository
+	userRepo       repository.UserRepository
+	auditRepo      repository.AuditLogRepository
+	authCfg        config.AuthConfig
+	log            *zap.Logger
+}
+
+func NewInvitationService(
+	invitationRepo repository.InvitationRepository,
+	userRepo repository.UserRepository,
+	auditRepo repository.AuditLogRepository,
+	authCfg config.AuthConfig,
+	log *zap.Logger,
+) *InvitationService {
+	return &InvitationService{
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		auditRepo:      auditRepo,
+		authCfg:        authCfg,
+		log:            log,
+	}
+}
+
+// Create issues an invitation for the email and role. The token is returned once
+// for the admin to pass on; only its hash is kept.
+func (s *InvitationService) Create(input dto.CreateInvitationInput, userID uuid.UUID) (*dto.InvitationResponse, error) {
+	existing, _ := s.userRepo.FindByEmail(input.Email)
+	if existing != nil {
+		return nil, errors.New("email already registered")
+	}
+
+	token, tokenHash, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	invitation := &model.Invitation{
+		ID:        uuid.New(),
+		Email:     input.Email,
+		Role:      input.Role,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(s.authCfg.InviteTTL),
+		CreatedBy: userID,
+	}
+
+	if err := s.invitationRepo.Create(invitation); err != nil {
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	afterJSON, _ := json.Marshal(invitation)
+	_ = s.auditRepo.Create(&model.AuditLog{
+		ID:         uuid.New(),
+		Entity:     "invitation",
+		EntityID:   invitation.ID,
+		Action:     "CREATE",
+		UserID:     userID,
+		AfterValue: afterJSON,
+	})
+
+	s.log.Info("Invitation created",
+		zap.String("invitation_id", invitation.ID.String()),
+		zap.String("email", invitation.Email),
+		zap.String("role", invitation.Role),
+	)
+
+	return &dto.InvitationResponse{Invitation: *invitation, Token: token}, nil
+}
+
+// GetAll returns invitations, only those that can still be accepted when pendingOnly is set
+func (s *InvitationService) GetAll(pendingOnly bool) ([]model.Invitation, error) {
+	return s.invitationRepo.FindAll(pendingOnly)
+}
+
+// Revoke stops an invitation from being accepted
+func (s *InvitationService) Revoke(id uuid.UUID, userID uuid.UUID) (*model.Invitation, error) {
+	invitation, err := s.invitationRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("invitation not found")
+	}
+	if invitation.AcceptedAt != nil {
+		return nil, errors.New("invitation already accepted")
+	}
+	if invitation.RevokedAt != nil {
+		return invitation, nil
+	}
+
+	beforeJSON, _ := json.Marshal(invitation)
+	now := time.Now()
+	invitation.RevokedAt = &now
+
+	if err := s.invitationRepo.Update(invitation); err != nil {
+		return nil, fmt.Errorf("failed to revoke invitation: %w", err)
+	}
+
+	afterJSON, _ := json.Marshal(invitation)
+	_ = s.auditRepo.Create(&model.AuditLog{
+		ID:          uuid.New(),
+		Entity:      "invitation",
+		EntityID:    invitation.ID,
+		Action:      "REVOKE",
+		UserID:      userID,
+		BeforeValue: beforeJSON,
+		AfterValue:  afterJSON,
+	})
+
+	s.log.Info("Invitation revoked", zap.String("invitation_id", invitation.ID.String()))
+
+	return invitation, nil
+}
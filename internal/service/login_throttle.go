@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/infrastructure"
+	"github.com/senoagung27/warehousex/internal/model"
+	"go.uber.org/zap"
+)
+
+// LoginThrottledError is returned while logins for the account or client IP are
+// refused after failed attempts; RetryAfter is when to try again
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		return "account locked after too many failed logins; try again later"
+	}
+	return "too many failed logins; try again later"
+}
+
+// checkLoginThrottle refuses a login while the account or IP is backing off or the
+// account is locked
+func (s *AuthService) checkLoginThrottle(ctx context.Context, user *model.User, email, clientIP string) error {
+	wait, err := s.redisClient.LoginBlockedFor(ctx, infrastructure.LoginAccountSubject(email), infrastructure.LoginIPSubject(clientIP))
+	if err != nil {
+		return err
+	}
+	if user != nil {
+		if locked := user.LockedFor(time.Now()); locked > 0 {
+			return &LoginThrottledError{RetryAfter: locked, Locked: true}
+		}
+	}
+	if wait > 0 {
+		return &LoginThrottledError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// loginFailed counts a failed login against the account and IP, backs both off
+// once past their free attempts and locks the account at the lockout threshold.
+// user is nil when no account has the email.
+func (s *AuthService) loginFailed(ctx context.Context, user *model.User, email, clientIP string) {
+	accountSubject := infrastructure.LoginAccountSubject(email)
+	accountFailures := s.recordLoginFailure(ctx, accountSubject, s.loginCfg.AccountFreeAttempts)
+	s.recordLoginFailure(ctx, infrastructure.LoginIPSubject(clientIP), s.loginCfg.IPFreeAttempts)
+
+	if user == nil || s.loginCfg.LockoutThreshold <= 0 || accountFailures < int64(s.loginCfg.LockoutThreshold) {
+		return
+	}
+
+	beforeJSON, _ := json.Marshal(user)
+	lockedUntil := time.Now().Add(s.loginCfg.LockoutDuration)
+	user.LockedUntil = &lockedUntil
+
+	if err := s.userRepo.SetLockedUntil(user.ID, &lockedUntil); err != nil {
+		s.log.Error("Failed to lock account", zap.String("user_id", user.ID.String()), zap.Error(err))
+		return
+	}
+	// The lockout replaces the backoff; once it ends the account starts afresh
+	if err := s.redisClient.ClearLoginFailures(ctx, accountSubject); err != nil {
+		s.log.Error("Failed to clear login failures", zap.String("user_id", user.ID.String()), zap.Error(err))
+	}
+
+	afterJSON, _ := json.Marshal(user)
+	_ = s.auditRepo.Create(&model.AuditLog{
+		ID:          uuid.New(),
+		Entity:      "user",
+		EntityID:    user.ID,
+		Action:      "LOCKOUT",
+		UserID:      model.SystemUserID,
+		BeforeValue: beforeJSON,
+		AfterValue:  afterJSON,
+	})
+
+	s.log.Warn("Account locked after failed logins",
+		zap.String("user_id", user.ID.String()),
+		zap.String("client_ip", clientIP),
+		zap.Time("locked_until", lockedUntil),
+	)
+}
+
+// recordLoginFailure counts a failure for the subject and blocks it for the
+// backoff once past its free attempts; it returns the failure count
+func (s *AuthService) recordLoginFailure(ctx context.Context, subject string, freeAttempts int) int64 {
+	failures, err := s.redisClient.RecordLoginFailure(ctx, subject, s.loginCfg.Window)
+	if err != nil {
+		s.log.Error("Failed to record login failure", zap.String("subject", subject), zap.Error(err))
+		return 0
+	}
+
+	if backoff := loginBackoff(failures-int64(freeAttempts), s.loginCfg.BackoffBase, s.loginCfg.BackoffMax); backoff > 0 {
+		if err := s.redisClient.BlockLogin(ctx, subject, backoff); err != nil {
+			s.log.Error("Failed to block login", zap.String("subject", subject), zap.Error(err))
+		}
+	}
+	return failures
+}
+
+// ResetLoginFailures forgets the failed logins of the account, e.g. when an admin
+// unlocks it
+func (s *AuthService) ResetLoginFailures(ctx context.Context, email string) error {
+	return s.redisClient.ClearLoginFailures(ctx, infrastructure.LoginAccountSubject(email))
+}
+
+// loginBackoff returns the block after the nth failure past the free attempts,
+// doubling from base up to max
+func loginBackoff(n int64, base, max time.Duration) time.Duration {
+	if n <= 0 || base <= 0 {
+		return 0
+	}
+	backoff := base
+	for i := int64(1); i < n && backoff < max; i++ {
+		backoff *= 2
+	}
+	if backoff > max {
+		backoff = max
+	}
+	return backoff
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// defaultApprovalSteps is the chain of requests no active policy matches
+var defaultApprovalSteps = []string{model.RoleSupervisor}
+
+// assignApprovals fixes the approval chain of a new request. Every active policy is
+// checked against the quantity and value of the request's lines in its category; the
+// matching policy with the longest chain wins, the oldest one on a tie.
+func (s *RequestService) assignApprovals(req *model.Request, items map[uuid.UUID]*model.Inventory) error {
+	policies, err := s.policyRepo.FindAll(true)
+	if err != nil {
+		return fmt.Errorf("failed to load approval policies: %w", err)
+	}
+
+	var chosen *model.ApprovalPolicy
+	for i := range policies {
+		policy := &policies[i]
+
+		quantity, value := 0, 0.0
+		for _, line := range req.Lines {
+			item := items[line.ItemID]
+			if policy.Category != "" && item.Category != policy.Category {
+				continue
+			}
+			quantity += line.Quantity
+			value += float64(line.Quantity) * item.UnitValue
+		}
+
+		if policy.Matches(req.Type, quantity, value) && (chosen == nil || len(policy.Steps) > len(chosen.Steps)) {
+			chosen = policy
+		}
+	}
+
+	req.Approvals = nil
+	if chosen == nil {
+		for i, role := range defaultApprovalSteps {
+			req.Approvals = append(req.Approvals, model.RequestApproval{
+				ID:        uuid.New(),
+				RequestID: req.ID,
+				StepNo:    i + 1,
+				Role:      role,
+			})
+		}
+		return nil
+	}
+
+	for _, step := range chosen.Steps {
+		req.Approvals = append(req.Approvals, model.RequestApproval{
+			ID:        uuid.New(),
+			RequestID: req.ID,
+			StepNo:    step.StepNo,
+			Role:      step.Role,
+			PolicyID:  &chosen.ID,
+		})
+	}
+	return nil
+}
+
+// approver is who signs for a request: the acting user, the role whose authority
+// they use, and the original approver when they act as a delegate
+type approver struct {
+	userID     uuid.UUID
+	role       string
+	onBehalfOf *uuid.UUID
+}
+
+// is checks if the user is the approver or the one they act for
+func (a *approver) is(userID uuid.UUID) bool {
+	return a.userID == userID || (a.onBehalfOf != nil && *a.onBehalfOf == userID)
+}
+
+// resolveApprover decides under whose authority the user may act on a request: their
+// own role when it allows, otherwise an active delegation covering the request's type
+// whose delegator's role allows. With a step, the authority must be able to sign that
+// step; without one, any approving role will do (rejection).
+func (s *RequestService) resolveApprover(req *model.Request, step *model.RequestApproval, userID uuid.UUID, userRole string) (*approver, error) {
+	allows := func(role string) bool {
+		if step == nil {
+			return model.CanApprove(role)
+		}
+		return model.CanSignStep(role, step.Role)
+	}
+
+	// Signing rules only apply to approval steps
+	check := func(a *approver) error {
+		if step == nil {
+			return nil
+		}
+		return checkSigner(req, a)
+	}
+
+	var refused error
+	if allows(userRole) {
+		own := &approver{userID: userID, role: userRole}
+		if refused = check(own); refused == nil {
+			return own, nil
+		}
+	}
+
+	delegations, err := s.delegationRepo.FindActiveForDelegate(userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load delegations: %w", err)
+	}
+	for _, delegation := range delegations {
+		// A delegator who may not use their own account lends no authority
+		if delegation.Delegator == nil || delegation.Delegator.CheckAccount() != nil {
+			continue
+		}
+		if !delegation.Covers(req.Type, time.Now()) || !allows(delegation.Delegator.Role) {
+			continue
+		}
+		delegated := &approver{userID: userID, role: delegation.Delegator.Role, onBehalfOf: &delegation.DelegatorID}
+		if err := check(delegated); err != nil {
+			if refused == nil {
+				refused = err
+			}
+			continue
+		}
+		return delegated, nil
+	}
+
+	if refused != nil {
+		return nil, refused
+	}
+	if step == nil {
+		return nil, errors.New("insufficient permissions to reject requests")
+	}
+	return nil, fmt.Errorf("insufficient permissions: approval step %d needs a %s", step.StepNo, step.Role)
+}
+
+// checkSigner applies the signing rules to an approver: nobody approves their own
+// request, directly or through a delegate, and nobody signs two steps of one chain
+func checkSigner(req *model.Request, a *approver) error {
+	if a.is(req.CreatedBy) {
+		return errors.New("cannot approve your own request")
+	}
+	for _, signed := range req.Approvals {
+		if signed.ApprovedBy == nil {
+			continue
+		}
+		if a.is(*signed.ApprovedBy) || (signed.OnBehalfOf != nil && a.is(*signed.OnBehalfOf)) {
+			return errors.New("already signed an approval step of this request")
+		}
+	}
+	return nil
+}
+
+// nextStep returns the request's next approval step and whether it is the last
+func nextStep(req *model.Request) (*model.RequestApproval, bool, error) {
+	step := req.NextApproval()
+	if step == nil {
+		return nil, false, errors.New("request has no open approval step")
+	}
+	return step, step.StepNo == req.Approvals[len(req.Approvals)-1].StepNo, nil
+}
+
+// signStep signs the request's next approval step inside the approval transaction.
+// The request must have been re-read by lockRequest; if another approver signed
+// stepNo in the meantime the approval is refused so it can be retried.
+func (s *RequestService) signStep(tx *gorm.DB, req *model.Request, stepNo int, signer *approver) error {
+	step, _, err := nextStep(req)
+	if err != nil {
+		return err
+	}
+	if step.StepNo != stepNo {
+		return errors.New("approval conflict: another approver signed this step, retry")
+	}
+	if err := checkSigner(req, signer); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	step.ApprovedBy = &signer.userID
+	step.OnBehalfOf = signer.onBehalfOf
+	step.ApprovedAt = &now
+
+	if err := s.requestRepo.SignApprovalWithTx(tx, step); err != nil {
+		return fmt.Errorf("failed to sign approval step: %w", err)
+	}
+
+	afterJSON, _ := json.Marshal(step)
+	if err := s.auditRepo.CreateWithTx(tx, &model.AuditLog{
+		ID:         uuid.New(),
+		Entity:     "request",
+		EntityID:   req.ID,
+		Action:     "APPROVAL_STEP_SIGNED",
+		UserID:     signer.userID,
+		AfterValue: afterJSON,
+	}); err != nil {
+		return fmt.Errorf("failed to create audit log: %w", err)
+	}
+	return nil
+}
+
+// signIntermediateStep signs an approval step that is not the last of the chain;
+// the request stays PENDING
+func (s *RequestService) signIntermediateStep(req *model.Request, stepNo int, signer *approver) (*model.Request, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.lockRequest(tx, req, model.StatusApproved); err != nil {
+			return err
+		}
+		return s.signStep(tx, req, stepNo, signer)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Request approval step signed",
+		zap.String("request_id", req.ID.String()),
+		zap.Int("step", stepNo),
+		zap.String("approver_id", signer.userID.String()),
+	)
+
+	return s.requestRepo.FindByID(req.ID)
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/dto"
+	"github.com/senoagung27/warehousex/internal/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// AddComment appends a comment to a request's thread, recording the author's role
+func (s *RequestService) AddComment(requestID uuid.UUID, userID uuid.UUID, userRole string, input dto.CreateCommentInput) (*model.RequestComment, error) {
+	body := strings.TrimSpace(input.Body)
+	if body == "" {
+		return nil, errors.New("comment body is required")
+	}
+
+	if _, err := s.requestRepo.FindByID(requestID); err != nil {
+		return nil, errors.New("request not found")
+	}
+
+	comment := &model.RequestComment{
+		ID:        uuid.New(),
+		RequestID: requestID,
+		UserID:    userID,
+		Role:      userRole,
+		Body:      body,
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.requestRepo.CreateCommentWithTx(tx, comment); err != nil {
+			return fmt.Errorf("failed to create comment: %w", err)
+		}
+
+		afterJSON, _ := json.Marshal(comment)
+		if err := s.auditRepo.CreateWithTx(tx, &model.AuditLog{
+			ID:         uuid.New(),
+			Entity:     "request",
+			EntityID:   requestID,
+			Action:     "COMMENTED",
+			UserID:     userID,
+			AfterValue: afterJSON,
+		}); err != nil {
+			return fmt.Errorf("failed to create audit log: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Request comment added",
+		zap.String("request_id", requestID.String()),
+		zap.String("comment_id", comment.ID.String()),
+		zap.String("user_id", userID.String()),
+	)
+
+	return comment, nil
+}
+
+// GetComments returns a request's comment thread, oldest first
+func (s *RequestService) GetComments(requestID uuid.UUID) ([]model.RequestComment, error) {
+	if _, err := s.requestRepo.FindByID(requestID); err != nil {
+		return nil, errors.New("request not found")
+	}
+	return s.requestRepo.FindComments(requestID)
+}
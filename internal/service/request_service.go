@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/senoagung27/warehousex/internal/domain/repository"
@@ -18,51 +21,95 @@ import (
 var _ RequestServiceInterface = (*RequestService)(nil)
 
 type RequestService struct {
-	requestRepo   repository.RequestRepository
-	inventoryRepo repository.InventoryRepository
-	auditRepo     repository.AuditLogRepository
-	redisClient   *infrastructure.RedisClient
-	db            *gorm.DB
-	log           *zap.Logger
+	requestRepo    repository.RequestRepository
+	inventoryRepo  repository.InventoryRepository
+	stockRepo      repository.StockRepository
+	warehouseRepo  repository.WarehouseRepository
+	locationRepo   repository.LocationRepository
+	lotRepo        repository.LotRepository
+	serialRepo     repository.SerialRepository
+	movementRepo   repository.MovementRepository
+	policyRepo     repository.ApprovalPolicyRepository
+	delegationRepo repository.DelegationRepository
+	userRepo       repository.UserRepository
+	auditRepo      repository.AuditLogRepository
+	locker         infrastructure.Locker
+	notifier       infrastructure.Notifier
+	db             *gorm.DB
+	log            *zap.Logger
 }
 
 func NewRequestService(
 	requestRepo repository.RequestRepository,
 	inventoryRepo repository.InventoryRepository,
+	stockRepo repository.StockRepository,
+	warehouseRepo repository.WarehouseRepository,
+	locationRepo repository.LocationRepository,
+	lotRepo repository.LotRepository,
+	serialRepo repository.SerialRepository,
+	movementRepo repository.MovementRepository,
+	policyRepo repository.ApprovalPolicyRepository,
+	delegationRepo repository.DelegationRepository,
+	userRepo repository.UserRepository,
 	auditRepo repository.AuditLogRepository,
-	redisClient *infrastructure.RedisClient,
+	locker infrastructure.Locker,
+	notifier infrastructure.Notifier,
 	db *gorm.DB,
 	log *zap.Logger,
 ) *RequestService {
 	return &RequestService{
-		requestRepo:   requestRepo,
-		inventoryRepo: inventoryRepo,
-		auditRepo:     auditRepo,
-		redisClient:   redisClient,
-		db:            db,
-		log:           log,
+		requestRepo:    requestRepo,
+		inventoryRepo:  inventoryRepo,
+		stockRepo:      stockRepo,
+		warehouseRepo:  warehouseRepo,
+		locationRepo:   locationRepo,
+		lotRepo:        lotRepo,
+		serialRepo:     serialRepo,
+		movementRepo:   movementRepo,
+		policyRepo:     policyRepo,
+		delegationRepo: delegationRepo,
+		userRepo:       userRepo,
+		auditRepo:      auditRepo,
+		locker:         locker,
+		notifier:       notifier,
+		db:             db,
+		log:            log,
 	}
 }
 
 func (s *RequestService) CreateInbound(input dto.CreateRequestInput, userID uuid.UUID) (*model.Request, error) {
-	itemID, err := uuid.Parse(input.ItemID)
+	warehouseID, err := s.parseWarehouseID(input.WarehouseID)
 	if err != nil {
-		return nil, errors.New("invalid item ID")
+		return nil, err
 	}
 
-	_, err = s.inventoryRepo.FindByID(itemID)
+	lines, err := requestLines(dto.RequestLineInput{
+		ItemID:         input.ItemID,
+		Quantity:       input.Quantity,
+		LotNumber:      input.LotNumber,
+		ManufacturedAt: input.ManufacturedAt,
+		ExpiresAt:      input.ExpiresAt,
+		SerialNumbers:  input.SerialNumbers,
+	}, input.Lines)
 	if err != nil {
-		return nil, errors.New("inventory item not found")
+		return nil, err
 	}
 
 	req := &model.Request{
-		ID:        uuid.New(),
-		Type:      model.RequestTypeInbound,
-		Status:    model.StatusPending,
-		ItemID:    itemID,
-		Quantity:  input.Quantity,
-		Notes:     input.Notes,
-		CreatedBy: userID,
+		ID:          uuid.New(),
+		Type:        model.RequestTypeInbound,
+		Status:      model.StatusPending,
+		WarehouseID: warehouseID,
+		Notes:       input.Notes,
+		CreatedBy:   userID,
+	}
+
+	items, err := s.buildLines(req, lines)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.assignApprovals(req, items); err != nil {
+		return nil, err
 	}
 
 	if err := s.requestRepo.Create(req); err != nil {
@@ -81,40 +128,48 @@ func (s *RequestService) CreateInbound(input dto.CreateRequestInput, userID uuid
 
 	s.log.Info("Inbound request created",
 		zap.String("request_id", req.ID.String()),
-		zap.String("item_id", itemID.String()),
-		zap.Int("quantity", input.Quantity),
+		zap.String("warehouse_id", warehouseID.String()),
+		zap.Int("lines", len(req.Lines)),
+		zap.Int("quantity", req.Quantity),
 	)
 
 	return req, nil
 }
 
-func (s *RequestService) CreateOutbound(input dto.CreateRequestInput, userID uuid.UUID) (*model.Request, error) {
-	itemID, err := uuid.Parse(input.ItemID)
+func (s *RequestService) CreateOutbound(ctx context.Context, input dto.CreateRequestInput, userID uuid.UUID) (*model.Request, error) {
+	warehouseID, err := s.parseWarehouseID(input.WarehouseID)
 	if err != nil {
-		return nil, errors.New("invalid item ID")
+		return nil, err
 	}
 
-	item, err := s.inventoryRepo.FindByID(itemID)
+	lines, err := requestLines(dto.RequestLineInput{
+		ItemID:        input.ItemID,
+		Quantity:      input.Quantity,
+		SerialNumbers: input.SerialNumbers,
+	}, input.Lines)
 	if err != nil {
-		return nil, errors.New("inventory item not found")
+		return nil, err
 	}
 
-	if item.Quantity < input.Quantity {
-		return nil, fmt.Errorf("insufficient stock: available %d, requested %d", item.Quantity, input.Quantity)
+	req := &model.Request{
+		ID:          uuid.New(),
+		Type:        model.RequestTypeOutbound,
+		Status:      model.StatusPending,
+		WarehouseID: warehouseID,
+		Notes:       input.Notes,
+		CreatedBy:   userID,
 	}
 
-	req := &model.Request{
-		ID:        uuid.New(),
-		Type:      model.RequestTypeOutbound,
-		Status:    model.StatusPending,
-		ItemID:    itemID,
-		Quantity:  input.Quantity,
-		Notes:     input.Notes,
-		CreatedBy: userID,
+	items, err := s.buildLines(req, lines)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.assignApprovals(req, items); err != nil {
+		return nil, err
 	}
 
-	if err := s.requestRepo.Create(req); err != nil {
-		return nil, fmt.Errorf("failed to create outbound request: %w", err)
+	if err := s.createReserved(ctx, req); err != nil {
+		return nil, err
 	}
 
 	afterJSON, _ := json.Marshal(req)
@@ -129,18 +184,270 @@ func (s *RequestService) CreateOutbound(input dto.CreateRequestInput, userID uui
 
 	s.log.Info("Outbound request created",
 		zap.String("request_id", req.ID.String()),
-		zap.String("item_id", itemID.String()),
-		zap.Int("quantity", input.Quantity),
+		zap.String("warehouse_id", warehouseID.String()),
+		zap.Int("lines", len(req.Lines)),
+		zap.Int("quantity", req.Quantity),
+	)
+
+	return req, nil
+}
+
+func (s *RequestService) CreateTransfer(ctx context.Context, input dto.CreateTransferInput, userID uuid.UUID) (*model.Request, error) {
+	sourceID, err := s.parseWarehouseID(input.SourceWarehouseID)
+	if err != nil {
+		return nil, err
+	}
+	destinationID, err := s.parseWarehouseID(input.DestinationWarehouseID)
+	if err != nil {
+		return nil, err
+	}
+	if sourceID == destinationID {
+		return nil, errors.New("source and destination warehouse must differ")
+	}
+
+	lines, err := requestLines(dto.RequestLineInput{
+		ItemID:        input.ItemID,
+		Quantity:      input.Quantity,
+		SerialNumbers: input.SerialNumbers,
+	}, input.Lines)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &model.Request{
+		ID:                     uuid.New(),
+		Type:                   model.RequestTypeTransfer,
+		Status:                 model.StatusPending,
+		WarehouseID:            sourceID,
+		DestinationWarehouseID: &destinationID,
+		Notes:                  input.Notes,
+		CreatedBy:              userID,
+	}
+
+	items, err := s.buildLines(req, lines)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.assignApprovals(req, items); err != nil {
+		return nil, err
+	}
+
+	if err := s.createReserved(ctx, req); err != nil {
+		return nil, err
+	}
+
+	afterJSON, _ := json.Marshal(req)
+	_ = s.auditRepo.Create(&model.AuditLog{
+		ID:         uuid.New(),
+		Entity:     "request",
+		EntityID:   req.ID,
+		Action:     "CREATE_TRANSFER",
+		UserID:     userID,
+		AfterValue: afterJSON,
+	})
+
+	s.log.Info("Transfer request created",
+		zap.String("request_id", req.ID.String()),
+		zap.String("source_warehouse_id", sourceID.String()),
+		zap.String("destination_warehouse_id", destinationID.String()),
+		zap.Int("lines", len(req.Lines)),
+		zap.Int("quantity", req.Quantity),
 	)
 
 	return req, nil
 }
 
-func (s *RequestService) ApproveRequest(requestID uuid.UUID, approverID uuid.UUID, approverRole string) (*model.Request, error) {
-	if !model.CanApprove(approverRole) {
-		return nil, errors.New("insufficient permissions to approve requests")
+// requestLines returns the lines of a create input. A single-item request given
+// through the top-level item fields becomes one line.
+func requestLines(single dto.RequestLineInput, lines []dto.RequestLineInput) ([]dto.RequestLineInput, error) {
+	if len(lines) == 0 {
+		return []dto.RequestLineInput{single}, nil
+	}
+	if single.ItemID != "" || single.Quantity != 0 || single.LotNumber != "" || len(single.SerialNumbers) > 0 {
+		return nil, errors.New("give either item_id and quantity or lines, not both")
+	}
+	return lines, nil
+}
+
+// buildLines validates the lines of a new request and attaches them, their serial
+// numbers and the header totals to the request. It returns the lines' items.
+func (s *RequestService) buildLines(req *model.Request, inputs []dto.RequestLineInput) (map[uuid.UUID]*model.Inventory, error) {
+	items := make(map[uuid.UUID]*model.Inventory, len(inputs))
+	seenSerials := make(map[string]bool)
+	for i, input := range inputs {
+		line := model.RequestLine{
+			ID:        uuid.New(),
+			RequestID: req.ID,
+			LineNo:    i + 1,
+			Quantity:  input.Quantity,
+		}
+
+		item, err := s.buildLine(req, &line, input, seenSerials)
+		if err != nil {
+			if len(inputs) > 1 {
+				return nil, fmt.Errorf("line %d: %w", line.LineNo, err)
+			}
+			return nil, err
+		}
+
+		items[item.ID] = item
+		req.Lines = append(req.Lines, line)
+		req.Quantity += line.Quantity
+	}
+
+	if len(req.Lines) == 1 {
+		req.ItemID = &req.Lines[0].ItemID
+	}
+	return items, nil
+}
+
+// buildLine validates one line input. Serial numbers must be unique per item across
+// the whole request; seenSerials tracks the ones already named.
+func (s *RequestService) buildLine(req *model.Request, line *model.RequestLine, input dto.RequestLineInput, seenSerials map[string]bool) (*model.Inventory, error) {
+	itemID, err := uuid.Parse(input.ItemID)
+	if err != nil {
+		return nil, errors.New("invalid item ID")
+	}
+
+	item, err := s.inventoryRepo.FindByID(itemID)
+	if err != nil {
+		return nil, errors.New("inventory item not found")
+	}
+	line.ItemID = itemID
+
+	// Lots are only recorded on receipt; outbound and transfer lots are allocated at approval
+	if req.Type == model.RequestTypeInbound {
+		if input.LotNumber == "" && (input.ManufacturedAt != nil || input.ExpiresAt != nil) {
+			return nil, errors.New("lot_number is required when lot dates are given")
+		}
+		if input.ManufacturedAt != nil && input.ExpiresAt != nil && input.ExpiresAt.Before(*input.ManufacturedAt) {
+			return nil, errors.New("expires_at cannot be before manufactured_at")
+		}
+		line.LotNumber = input.LotNumber
+		line.ManufacturedAt = input.ManufacturedAt
+		line.ExpiresAt = input.ExpiresAt
+	}
+
+	serials, err := s.validateSerials(item, req.Type, req.WarehouseID, input.Quantity, input.SerialNumbers)
+	if err != nil {
+		return nil, err
+	}
+	for _, serial := range serials {
+		key := itemID.String() + "/" + serial.SerialNumber
+		if seenSerials[key] {
+			return nil, fmt.Errorf("duplicate serial number: %s", serial.SerialNumber)
+		}
+		seenSerials[key] = true
+
+		serial.RequestID = req.ID
+		serial.LineID = line.ID
+		req.Serials = append(req.Serials, serial)
+	}
+	return item, nil
+}
+
+// createReserved stores an outbound or transfer request together with a reservation
+// of every line's quantity on the source balances. The balances are locked like an
+// approval so concurrent requests cannot reserve the same units.
+func (s *RequestService) createReserved(ctx context.Context, req *model.Request) error {
+	keys := lineStockKeys(req, req.WarehouseID)
+
+	locks, err := s.lockBalances(ctx, keys)
+	if err != nil {
+		return err
+	}
+	defer locks.release()
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		stocks, err := s.lockStocks(tx, locks, keys, nil)
+		if err != nil {
+			return err
+		}
+
+		for i := range req.Lines {
+			line := &req.Lines[i]
+			stock := stocks[stockKey{itemID: line.ItemID, warehouseID: req.WarehouseID}]
+
+			available := 0
+			if stock != nil {
+				available = stock.Quantity - stock.Reserved
+			}
+			if available < line.Quantity {
+				return lineError(req, line, fmt.Errorf("insufficient stock: available %d, requested %d", available, line.Quantity))
+			}
+
+			stock.Reserved += line.Quantity
+			line.ReservedQuantity = line.Quantity
+		}
+
+		if err := s.saveStocks(tx, keys, stocks); err != nil {
+			return fmt.Errorf("failed to reserve stock: %w", err)
+		}
+
+		if err := s.requestRepo.CreateWithTx(tx, req); err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		return locks.check()
+	})
+}
+
+// releaseReservation returns what the request's lines still hold to the available
+// balances. The caller holds the locks of reservedStockKeys.
+func (s *RequestService) releaseReservation(tx *gorm.DB, locks *heldLocks, req *model.Request) error {
+	keys := reservedStockKeys(req)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	stocks, err := s.lockStocks(tx, locks, keys, nil)
+	if err != nil {
+		return err
+	}
+
+	for i := range req.Lines {
+		line := &req.Lines[i]
+		if line.ReservedQuantity == 0 {
+			continue
+		}
+		if stock := stocks[stockKey{itemID: line.ItemID, warehouseID: req.WarehouseID}]; stock != nil {
+			stock.Reserved -= min(line.ReservedQuantity, stock.Reserved)
+		}
+		line.ReservedQuantity = 0
+	}
+
+	if err := s.saveStocks(tx, keys, stocks); err != nil {
+		return fmt.Errorf("failed to release reservation: %w", err)
+	}
+	return nil
+}
+
+// lockRequest re-reads a request under a row lock inside the transaction and checks
+// that it can still move to the given status, so a concurrent approval, rejection
+// shipment or cancellation of the same request cannot both apply
+func (s *RequestService) lockRequest(tx *gorm.DB, req *model.Request, to string) error {
+	current, err := s.requestRepo.FindByIDForUpdate(tx, req.ID)
+	if err != nil {
+		return errors.New("request not found")
+	}
+	if !model.ValidTransition(current.Status, to) {
+		return fmt.Errorf("cannot change request with status %s to %s", current.Status, to)
+	}
+
+	req.Status = current.Status
+	req.Serials = current.Serials
+	req.Approvals = current.Approvals
+	for i := range req.Lines {
+		if line := findLine(current, req.Lines[i].ID); line != nil {
+			req.Lines[i].ReservedQuantity = line.ReservedQuantity
+			req.Lines[i].ShippedQuantity = line.ShippedQuantity
+		}
 	}
+	return nil
+}
 
+// ApproveRequest signs the request's next approval step with the approver's own
+// authority or one delegated to them. The last step also applies the request.
+func (s *RequestService) ApproveRequest(ctx context.Context, requestID uuid.UUID, approverID uuid.UUID, approverRole string, input dto.ApproveRequestInput) (*model.Request, error) {
 	req, err := s.requestRepo.FindByID(requestID)
 	if err != nil {
 		return nil, errors.New("request not found")
@@ -150,33 +457,133 @@ func (s *RequestService) ApproveRequest(requestID uuid.UUID, approverID uuid.UUI
 		return nil, fmt.Errorf("cannot approve request with status: %s", req.Status)
 	}
 
-	if req.CreatedBy == approverID {
-		return nil, errors.New("cannot approve your own request")
+	step, last, err := nextStep(req)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := s.resolveApprover(req, step, approverID, approverRole)
+	if err != nil {
+		return nil, err
+	}
+
+	// Steps before the last are only signed; the last one also applies the request
+	if !last {
+		return s.signIntermediateStep(req, step.StepNo, signer)
+	}
+
+	if err := s.resolveLineBins(req, input); err != nil {
+		return nil, err
+	}
+
+	switch req.Type {
+	case model.RequestTypeOutbound:
+		return s.processOutboundApproval(ctx, req, signer, step.StepNo)
+	case model.RequestTypeTransfer:
+		return s.processTransferApproval(ctx, req, signer, step.StepNo)
 	}
 
-	ctx := context.Background()
+	return s.processInboundApproval(req, signer, step.StepNo)
+}
+
+// resolveLineBins applies the bins chosen at approval to the request's lines. The
+// top-level bins are the default for every line; per-line entries override them.
+func (s *RequestService) resolveLineBins(req *model.Request, input dto.ApproveRequestInput) error {
+	overrides := make(map[uuid.UUID]dto.ApproveLineInput, len(input.Lines))
+	for _, lineInput := range input.Lines {
+		lineID, err := uuid.Parse(lineInput.LineID)
+		if err != nil {
+			return errors.New("invalid line ID")
+		}
+		overrides[lineID] = lineInput
+	}
+
+	for i := range req.Lines {
+		line := &req.Lines[i]
+		binID, destinationBinID := input.BinID, input.DestinationBinID
+		if lineInput, ok := overrides[line.ID]; ok {
+			delete(overrides, line.ID)
+			if lineInput.BinID != "" {
+				binID = lineInput.BinID
+			}
+			if lineInput.DestinationBinID != "" {
+				destinationBinID = lineInput.DestinationBinID
+			}
+		}
 
-	if req.Type == model.RequestTypeOutbound {
-		return s.processOutboundApproval(ctx, req, approverID)
+		var err error
+		line.BinID, err = s.resolveBin(binID, req.WarehouseID)
+		if err != nil {
+			return lineError(req, line, err)
+		}
+		if req.Type == model.RequestTypeTransfer && req.DestinationWarehouseID != nil {
+			line.DestinationBinID, err = s.resolveBin(destinationBinID, *req.DestinationWarehouseID)
+			if err != nil {
+				return lineError(req, line, err)
+			}
+		}
 	}
 
-	return s.processInboundApproval(req, approverID)
+	if len(overrides) > 0 {
+		return errors.New("approval names a line that is not on this request")
+	}
+	return nil
 }
 
-func (s *RequestService) processInboundApproval(req *model.Request, approverID uuid.UUID) (*model.Request, error) {
+func (s *RequestService) processInboundApproval(req *model.Request, signer *approver, stepNo int) (*model.Request, error) {
+	approverID := signer.userID
+	keys := lineStockKeys(req, req.WarehouseID)
+
 	err := s.db.Transaction(func(tx *gorm.DB) error {
-		item, err := s.inventoryRepo.FindByIDForUpdate(tx, req.ItemID)
+		if err := s.lockRequest(tx, req, model.StatusApproved); err != nil {
+			return err
+		}
+		if err := s.signStep(tx, req, stepNo, signer); err != nil {
+			return err
+		}
+
+		stocks, err := s.lockStocks(tx, nil, keys, func(stockKey) bool { return true })
 		if err != nil {
-			return fmt.Errorf("inventory item not found: %w", err)
+			return err
 		}
 
-		beforeJSON, _ := json.Marshal(item)
+		for i := range req.Lines {
+			line := &req.Lines[i]
+			stock := stocks[stockKey{itemID: line.ItemID, warehouseID: req.WarehouseID}]
+
+			beforeJSON, _ := json.Marshal(stock)
 
-		item.Quantity += req.Quantity
-		item.Version++
+			if err := s.putAway(tx, req, stock, line.BinID, line.Quantity, approverID); err != nil {
+				return lineError(req, line, err)
+			}
 
-		if err := s.inventoryRepo.UpdateWithTx(tx, item); err != nil {
-			return fmt.Errorf("failed to update inventory: %w", err)
+			if line.LotNumber != "" {
+				if err := s.receiveLot(tx, &model.Lot{
+					ItemID:         line.ItemID,
+					WarehouseID:    req.WarehouseID,
+					LotNumber:      line.LotNumber,
+					ManufacturedAt: line.ManufacturedAt,
+					ExpiresAt:      line.ExpiresAt,
+				}, line.Quantity); err != nil {
+					return lineError(req, line, err)
+				}
+			}
+
+			afterJSON, _ := json.Marshal(stock)
+			if err := s.auditRepo.CreateWithTx(tx, &model.AuditLog{
+				ID:          uuid.New(),
+				Entity:      "inventory",
+				EntityID:    line.ItemID,
+				Action:      "INBOUND_APPROVED",
+				UserID:      approverID,
+				BeforeValue: beforeJSON,
+				AfterValue:  afterJSON,
+			}); err != nil {
+				return fmt.Errorf("failed to create audit log: %w", err)
+			}
+		}
+
+		if err := s.moveSerials(tx, req, approverID); err != nil {
+			return err
 		}
 
 		req.Status = model.StatusCompleted
@@ -186,19 +593,6 @@ func (s *RequestService) processInboundApproval(req *model.Request, approverID u
 			return fmt.Errorf("failed to update request: %w", err)
 		}
 
-		afterJSON, _ := json.Marshal(item)
-		if err := s.auditRepo.CreateWithTx(tx, &model.AuditLog{
-			ID:          uuid.New(),
-			Entity:      "inventory",
-			EntityID:    item.ID,
-			Action:      "INBOUND_APPROVED",
-			UserID:      approverID,
-			BeforeValue: beforeJSON,
-			AfterValue:  afterJSON,
-		}); err != nil {
-			return fmt.Errorf("failed to create audit log: %w", err)
-		}
-
 		return nil
 	})
 
@@ -214,55 +608,77 @@ func (s *RequestService) processInboundApproval(req *model.Request, approverID u
 	return s.requestRepo.FindByID(req.ID)
 }
 
-func (s *RequestService) processOutboundApproval(ctx context.Context, req *model.Request, approverID uuid.UUID) (*model.Request, error) {
-	lockValue, err := s.redisClient.AcquireLock(ctx, req.ItemID)
+// processOutboundApproval commits an outbound request for picking. Stock stays
+// reserved and only leaves the warehouse as shipments are recorded; lines whose
+// reservation is short (requests created before reservations existed) are topped
+// up here or the approval fails.
+func (s *RequestService) processOutboundApproval(ctx context.Context, req *model.Request, signer *approver, stepNo int) (*model.Request, error) {
+	approverID := signer.userID
+	keys := lineStockKeys(req, req.WarehouseID)
+
+	locks, err := s.lockBalances(ctx, keys)
 	if err != nil {
-		return nil, fmt.Errorf("lock conflict: %w", err)
+		return nil, err
 	}
-	defer func() {
-		_ = s.redisClient.ReleaseLock(ctx, req.ItemID, lockValue)
-	}()
+	defer locks.release()
 
 	err = s.db.Transaction(func(tx *gorm.DB) error {
-		item, err := s.inventoryRepo.FindByIDForUpdate(tx, req.ItemID)
-		if err != nil {
-			return fmt.Errorf("inventory item not found: %w", err)
+		if err := s.lockRequest(tx, req, model.StatusApproved); err != nil {
+			return err
+		}
+		if err := s.signStep(tx, req, stepNo, signer); err != nil {
+			return err
 		}
 
-		if item.Quantity < req.Quantity {
-			return fmt.Errorf("insufficient stock: available %d, requested %d", item.Quantity, req.Quantity)
+		stocks, err := s.lockStocks(tx, locks, keys, nil)
+		if err != nil {
+			return err
 		}
 
-		beforeJSON, _ := json.Marshal(item)
+		for i := range req.Lines {
+			line := &req.Lines[i]
+			short := line.Quantity - line.ReservedQuantity
+			if short <= 0 {
+				continue
+			}
 
-		item.Quantity -= req.Quantity
-		item.Version++
+			stock := stocks[stockKey{itemID: line.ItemID, warehouseID: req.WarehouseID}]
+			available := 0
+			if stock != nil {
+				available = stock.Quantity - stock.Reserved
+			}
+			if available < short {
+				return lineError(req, line, fmt.Errorf("insufficient stock: available %d, requested %d", available, short))
+			}
 
-		if err := s.inventoryRepo.UpdateWithTx(tx, item); err != nil {
-			return fmt.Errorf("failed to update inventory: %w", err)
+			stock.Reserved += short
+			line.ReservedQuantity += short
 		}
 
-		req.Status = model.StatusCompleted
+		if err := s.saveStocks(tx, keys, stocks); err != nil {
+			return fmt.Errorf("failed to reserve stock: %w", err)
+		}
+
+		req.Status = model.StatusApproved
 		req.ApprovedBy = &approverID
 
 		if err := s.requestRepo.UpdateWithTx(tx, req); err != nil {
 			return fmt.Errorf("failed to update request: %w", err)
 		}
 
-		afterJSON, _ := json.Marshal(item)
+		afterJSON, _ := json.Marshal(req)
 		if err := s.auditRepo.CreateWithTx(tx, &model.AuditLog{
-			ID:          uuid.New(),
-			Entity:      "inventory",
-			EntityID:    item.ID,
-			Action:      "OUTBOUND_APPROVED",
-			UserID:      approverID,
-			BeforeValue: beforeJSON,
-			AfterValue:  afterJSON,
+			ID:         uuid.New(),
+			Entity:     "request",
+			EntityID:   req.ID,
+			Action:     "OUTBOUND_APPROVED",
+			UserID:     approverID,
+			AfterValue: afterJSON,
 		}); err != nil {
 			return fmt.Errorf("failed to create audit log: %w", err)
 		}
 
-		return nil
+		return locks.check()
 	})
 
 	if err != nil {
@@ -277,45 +693,1039 @@ func (s *RequestService) processOutboundApproval(ctx context.Context, req *model
 	return s.requestRepo.FindByID(req.ID)
 }
 
-func (s *RequestService) RejectRequest(requestID uuid.UUID, approverID uuid.UUID, approverRole string) (*model.Request, error) {
-	if !model.CanApprove(approverRole) {
-		return nil, errors.New("insufficient permissions to reject requests")
-	}
-
+// ShipRequest records a shipment against an approved outbound request. Every shipped
+// line is picked for the quantity actually shipped, consuming its reservation. The
+// request is PARTIALLY_FULFILLED while any line is outstanding and completes once
+// every line has been shipped in full.
+func (s *RequestService) ShipRequest(ctx context.Context, requestID uuid.UUID, userID uuid.UUID, input dto.CreateShipmentInput) (*model.Request, error) {
 	req, err := s.requestRepo.FindByID(requestID)
 	if err != nil {
 		return nil, errors.New("request not found")
 	}
 
-	if !model.ValidTransition(req.Status, model.StatusRejected) {
-		return nil, fmt.Errorf("cannot reject request with status: %s", req.Status)
+	if req.Type != model.RequestTypeOutbound {
+		return nil, errors.New("only outbound requests are shipped")
+	}
+	if !model.ValidTransition(req.Status, model.StatusPartiallyFulfilled) {
+		return nil, fmt.Errorf("cannot ship request with status: %s", req.Status)
+	}
+
+	shipment := &model.Shipment{
+		ID:        uuid.New(),
+		RequestID: req.ID,
+		ShippedBy: userID,
+		Notes:     input.Notes,
 	}
+	serials := make(map[uuid.UUID][]string, len(input.Lines))
+	shipped := make(map[uuid.UUID]*model.RequestLine, len(input.Lines))
+	var keys []stockKey
+
+	for _, lineInput := range input.Lines {
+		lineID, err := uuid.Parse(lineInput.LineID)
+		if err != nil {
+			return nil, errors.New("invalid line ID")
+		}
+		line := findLine(req, lineID)
+		if line == nil {
+			return nil, errors.New("shipment names a line that is not on this request")
+		}
+		if shipped[lineID] != nil {
+			return nil, lineError(req, line, errors.New("line is named more than once in the shipment"))
+		}
+		shipped[lineID] = line
 
-	req.Status = model.StatusRejected
-	req.ApprovedBy = &approverID
+		binID := line.BinID
+		if lineInput.BinID != "" {
+			if binID, err = s.resolveBin(lineInput.BinID, req.WarehouseID); err != nil {
+				return nil, lineError(req, line, err)
+			}
+		}
 
-	if err := s.requestRepo.Update(req); err != nil {
-		return nil, fmt.Errorf("failed to reject request: %w", err)
+		shipment.Lines = append(shipment.Lines, model.ShipmentLine{
+			ID:         uuid.New(),
+			ShipmentID: shipment.ID,
+			LineID:     lineID,
+			Quantity:   lineInput.Quantity,
+			BinID:      binID,
+		})
+		serials[lineID] = lineInput.SerialNumbers
+		keys = append(keys, stockKey{itemID: line.ItemID, warehouseID: req.WarehouseID})
 	}
 
-	afterJSON, _ := json.Marshal(req)
-	_ = s.auditRepo.Create(&model.AuditLog{
-		ID:         uuid.New(),
-		Entity:     "request",
-		EntityID:   req.ID,
-		Action:     "REJECTED",
-		UserID:     approverID,
-		AfterValue: afterJSON,
+	locks, err := s.lockBalances(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	defer locks.release()
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.lockRequest(tx, req, model.StatusPartiallyFulfilled); err != nil {
+			return err
+		}
+
+		// Quantities are checked against the line as re-read under the request lock
+		for _, shipmentLine := range shipment.Lines {
+			line := findLine(req, shipmentLine.LineID)
+			if shipmentLine.Quantity > line.Outstanding() {
+				return lineError(req, line, fmt.Errorf("cannot ship %d, only %d outstanding", shipmentLine.Quantity, line.Outstanding()))
+			}
+			if err := checkShipmentSerials(req, line, shipmentLine.Quantity, serials[line.ID]); err != nil {
+				return lineError(req, line, err)
+			}
+		}
+
+		if err := s.requestRepo.CreateShipmentWithTx(tx, shipment); err != nil {
+			return fmt.Errorf("failed to record shipment: %w", err)
+		}
+
+		stocks, err := s.lockStocks(tx, locks, keys, nil)
+		if err != nil {
+			return err
+		}
+
+		for _, shipmentLine := range shipment.Lines {
+			line := findLine(req, shipmentLine.LineID)
+			stock := stocks[stockKey{itemID: line.ItemID, warehouseID: req.WarehouseID}]
+			if stock == nil {
+				return lineError(req, line, errors.New("insufficient stock: item has no balance at this warehouse"))
+			}
+
+			beforeJSON, _ := json.Marshal(stock)
+
+			if err := s.pick(tx, req, line, stock, shipmentLine.BinID, shipmentLine.Quantity, userID); err != nil {
+				return lineError(req, line, err)
+			}
+
+			if _, err := s.allocateLots(tx, req, line, &shipment.ID, stock, shipmentLine.Quantity); err != nil {
+				return lineError(req, line, err)
+			}
+
+			if lineSerials := serials[line.ID]; len(lineSerials) > 0 {
+				if err := s.moveLineSerials(tx, req, line.ItemID, lineSerials, userID); err != nil {
+					return lineError(req, line, err)
+				}
+				if err := s.requestRepo.ShipSerialsWithTx(tx, line.ID, shipment.ID, lineSerials); err != nil {
+					return lineError(req, line, err)
+				}
+			}
+
+			line.ShippedQuantity += shipmentLine.Quantity
+
+			afterJSON, _ := json.Marshal(stock)
+			if err := s.auditRepo.CreateWithTx(tx, &model.AuditLog{
+				ID:          uuid.New(),
+				Entity:      "inventory",
+				EntityID:    line.ItemID,
+				Action:      "OUTBOUND_SHIPPED",
+				UserID:      userID,
+				BeforeValue: beforeJSON,
+				AfterValue:  afterJSON,
+			}); err != nil {
+				return fmt.Errorf("failed to create audit log: %w", err)
+			}
+		}
+
+		req.Status = model.StatusCompleted
+		for _, line := range req.Lines {
+			if line.Outstanding() > 0 {
+				req.Status = model.StatusPartiallyFulfilled
+				break
+			}
+		}
+
+		if err := s.requestRepo.UpdateWithTx(tx, req); err != nil {
+			return fmt.Errorf("failed to update request: %w", err)
+		}
+
+		return locks.check()
 	})
 
-	s.log.Info("Request rejected",
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Outbound shipment recorded",
 		zap.String("request_id", req.ID.String()),
-		zap.String("approver_id", approverID.String()),
+		zap.String("shipment_id", shipment.ID.String()),
+		zap.String("status", req.Status),
 	)
 
 	return s.requestRepo.FindByID(req.ID)
 }
 
+// checkShipmentSerials checks the serial numbers shipped on a line: a line named
+// with serials must ship exactly quantity of them, each still unshipped on the line
+func checkShipmentSerials(req *model.Request, line *model.RequestLine, quantity int, serials []string) error {
+	unshipped := make(map[string]bool)
+	for _, requestSerial := range req.Serials {
+		if requestSerial.LineID == line.ID && requestSerial.ShipmentID == nil {
+			unshipped[requestSerial.SerialNumber] = true
+		}
+	}
+
+	if len(unshipped) == 0 {
+		if len(serials) > 0 {
+			return errors.New("line has no serial numbers; serial_numbers must be empty")
+		}
+		return nil
+	}
+	if len(serials) != quantity {
+		return fmt.Errorf("serialized line needs exactly %d serial numbers, got %d", quantity, len(serials))
+	}
+	for _, serial := range serials {
+		if !unshipped[serial] {
+			return fmt.Errorf("serial number %s is not an unshipped serial of this line", serial)
+		}
+		delete(unshipped, serial)
+	}
+	return nil
+}
+
+// findLine returns the request line with the given ID, or nil
+func findLine(req *model.Request, lineID uuid.UUID) *model.RequestLine {
+	for i := range req.Lines {
+		if req.Lines[i].ID == lineID {
+			return &req.Lines[i]
+		}
+	}
+	return nil
+}
+
+// processTransferApproval moves stock between two warehouses in one transaction.
+// All source and destination balances are locked (distributed lock, then SELECT FOR UPDATE)
+// in key order so two opposite transfers of the same items cannot deadlock.
+func (s *RequestService) processTransferApproval(ctx context.Context, req *model.Request, signer *approver, stepNo int) (*model.Request, error) {
+	approverID := signer.userID
+	if req.DestinationWarehouseID == nil {
+		return nil, errors.New("transfer request has no destination warehouse")
+	}
+	sourceID, destinationID := req.WarehouseID, *req.DestinationWarehouseID
+
+	keys := append(lineStockKeys(req, sourceID), lineStockKeys(req, destinationID)...)
+
+	locks, err := s.lockBalances(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	defer locks.release()
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.lockRequest(tx, req, model.StatusApproved); err != nil {
+			return err
+		}
+		if err := s.signStep(tx, req, stepNo, signer); err != nil {
+			return err
+		}
+
+		stocks, err := s.lockStocks(tx, locks, keys, func(key stockKey) bool {
+			return key.warehouseID == destinationID
+		})
+		if err != nil {
+			return err
+		}
+
+		for i := range req.Lines {
+			line := &req.Lines[i]
+			source := stocks[stockKey{itemID: line.ItemID, warehouseID: sourceID}]
+			destination := stocks[stockKey{itemID: line.ItemID, warehouseID: destinationID}]
+			if source == nil {
+				return lineError(req, line, errors.New("insufficient stock: item has no balance at source warehouse"))
+			}
+
+			beforeJSON, _ := json.Marshal(map[string]interface{}{
+				"source":      source,
+				"destination": destination,
+			})
+
+			if err := s.pick(tx, req, line, source, line.BinID, line.Quantity, approverID); err != nil {
+				return lineError(req, line, err)
+			}
+			if err := s.putAway(tx, req, destination, line.DestinationBinID, line.Quantity, approverID); err != nil {
+				return lineError(req, line, err)
+			}
+
+			// Allocated lots keep their number and dates at the destination
+			allocations, err := s.allocateLots(tx, req, line, nil, source, line.Quantity)
+			if err != nil {
+				return lineError(req, line, err)
+			}
+			for _, allocation := range allocations {
+				if err := s.receiveLot(tx, &model.Lot{
+					ItemID:         line.ItemID,
+					WarehouseID:    destinationID,
+					LotNumber:      allocation.Lot.LotNumber,
+					ManufacturedAt: allocation.Lot.ManufacturedAt,
+					ExpiresAt:      allocation.Lot.ExpiresAt,
+				}, allocation.Quantity); err != nil {
+					return lineError(req, line, err)
+				}
+			}
+
+			afterJSON, _ := json.Marshal(map[string]interface{}{
+				"source":      source,
+				"destination": destination,
+			})
+			if err := s.auditRepo.CreateWithTx(tx, &model.AuditLog{
+				ID:          uuid.New(),
+				Entity:      "inventory",
+				EntityID:    line.ItemID,
+				Action:      "TRANSFER_APPROVED",
+				UserID:      approverID,
+				BeforeValue: beforeJSON,
+				AfterValue:  afterJSON,
+			}); err != nil {
+				return fmt.Errorf("failed to create audit log: %w", err)
+			}
+		}
+
+		if err := s.moveSerials(tx, req, approverID); err != nil {
+			return err
+		}
+
+		req.Status = model.StatusCompleted
+		req.ApprovedBy = &approverID
+
+		if err := s.requestRepo.UpdateWithTx(tx, req); err != nil {
+			return fmt.Errorf("failed to update request: %w", err)
+		}
+
+		return locks.check()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Transfer request approved",
+		zap.String("request_id", req.ID.String()),
+		zap.String("approver_id", approverID.String()),
+	)
+
+	return s.requestRepo.FindByID(req.ID)
+}
+
+// putAway adds quantity to a locked warehouse balance and, when a bin is given, to that bin
+func (s *RequestService) putAway(tx *gorm.DB, req *model.Request, stock *model.InventoryStock, binID *uuid.UUID, quantity int, userID uuid.UUID) error {
+	if err := s.updateStock(tx, req, stock, quantity, userID); err != nil {
+		return err
+	}
+
+	if binID == nil {
+		return nil
+	}
+
+	bin, err := s.stockRepo.FindOrCreateBinForUpdate(tx, stock.ItemID, stock.WarehouseID, *binID)
+	if err != nil {
+		return fmt.Errorf("failed to lock bin balance: %w", err)
+	}
+
+	bin.Quantity += quantity
+	bin.Version++
+
+	if err := s.stockRepo.UpdateBinWithTx(tx, bin); err != nil {
+		return fmt.Errorf("failed to update bin: %w", err)
+	}
+	return nil
+}
+
+// pick removes quantity from a locked warehouse balance. With a bin it is taken
+// from that bin; without one it may only come from stock not yet put away.
+func (s *RequestService) pick(tx *gorm.DB, req *model.Request, line *model.RequestLine, stock *model.InventoryStock, binID *uuid.UUID, quantity int, userID uuid.UUID) error {
+	// The line may use its own reservation plus whatever nobody else holds
+	consumed := min(quantity, line.ReservedQuantity, stock.Reserved)
+	if available := stock.Quantity - stock.Reserved + consumed; available < quantity {
+		return fmt.Errorf("insufficient stock: available %d, requested %d", available, quantity)
+	}
+
+	if binID != nil {
+		bin, err := s.stockRepo.FindBinForUpdate(tx, stock.ItemID, *binID)
+		if err != nil {
+			return fmt.Errorf("insufficient stock: item has no balance in pick bin")
+		}
+		if bin.Quantity < quantity {
+			return fmt.Errorf("insufficient stock: bin has %d, requested %d", bin.Quantity, quantity)
+		}
+
+		bin.Quantity -= quantity
+		bin.Version++
+
+		if err := s.stockRepo.UpdateBinWithTx(tx, bin); err != nil {
+			return fmt.Errorf("failed to update bin: %w", err)
+		}
+	} else {
+		binned, err := s.stockRepo.SumBinnedWithTx(tx, stock.ItemID, stock.WarehouseID)
+		if err != nil {
+			return fmt.Errorf("failed to read bin balances: %w", err)
+		}
+		if unbinned := stock.Quantity - binned; unbinned < quantity {
+			return fmt.Errorf("insufficient stock: %d not in a bin, requested %d; specify a pick bin", unbinned, quantity)
+		}
+	}
+
+	stock.Reserved -= consumed
+	line.ReservedQuantity -= consumed
+
+	return s.updateStock(tx, req, stock, -quantity, userID)
+}
+
+// updateStock applies a signed delta to a locked balance and appends the matching
+// entry to the stock movement ledger in the same transaction
+func (s *RequestService) updateStock(tx *gorm.DB, req *model.Request, stock *model.InventoryStock, delta int, userID uuid.UUID) error {
+	stock.Quantity += delta
+	stock.Version++
+
+	if err := s.stockRepo.UpdateWithTx(tx, stock); err != nil {
+		return fmt.Errorf("failed to update inventory: %w", err)
+	}
+
+	if err := s.movementRepo.CreateWithTx(tx, &model.StockMovement{
+		ID:           uuid.New(),
+		StockID:      stock.ID,
+		ItemID:       stock.ItemID,
+		WarehouseID:  stock.WarehouseID,
+		RequestID:    &req.ID,
+		Type:         req.Type,
+		Delta:        delta,
+		BalanceAfter: stock.Quantity,
+		UserID:       &userID,
+	}); err != nil {
+		return fmt.Errorf("failed to record stock movement: %w", err)
+	}
+	return nil
+}
+
+// receiveLot adds quantity to a lot at a warehouse, creating the lot on first receipt.
+// Callers must hold the item/warehouse balance lock.
+func (s *RequestService) receiveLot(tx *gorm.DB, received *model.Lot, quantity int) error {
+	received.ID = uuid.New()
+	received.Version = 1
+
+	lot, err := s.lotRepo.FindOrCreateForUpdate(tx, received)
+	if err != nil {
+		return fmt.Errorf("failed to lock lot: %w", err)
+	}
+
+	if received.ExpiresAt != nil {
+		if lot.ExpiresAt != nil && !lot.ExpiresAt.Equal(*received.ExpiresAt) {
+			return fmt.Errorf("lot %s is already recorded with expiry %s", lot.LotNumber, lot.ExpiresAt.Format(time.DateOnly))
+		}
+		lot.ExpiresAt = received.ExpiresAt
+	}
+	if received.ManufacturedAt != nil && lot.ManufacturedAt == nil {
+		lot.ManufacturedAt = received.ManufacturedAt
+	}
+
+	lot.Quantity += quantity
+	lot.Version++
+
+	if err := s.lotRepo.UpdateWithTx(tx, lot); err != nil {
+		return fmt.Errorf("failed to update lot: %w", err)
+	}
+	return nil
+}
+
+// allocateLots consumes quantity from the unexpired lots of a locked balance
+// first-expired-first-out and records the chosen lots on the request line and, for
+// outbound, the shipment. Whatever the lots cannot cover is taken from stock received
+// without a lot; it fails when there is not enough of that either. stock is the
+// balance after the pick.
+func (s *RequestService) allocateLots(tx *gorm.DB, req *model.Request, line *model.RequestLine, shipmentID *uuid.UUID, stock *model.InventoryStock, quantity int) ([]model.RequestLot, error) {
+	lots, err := s.lotRepo.FindAvailableForUpdate(tx, stock.ItemID, stock.WarehouseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock lots: %w", err)
+	}
+
+	var allocations []model.RequestLot
+	remaining := quantity
+	for i := range lots {
+		if remaining == 0 {
+			break
+		}
+		lot := &lots[i]
+		take := min(remaining, lot.Quantity)
+
+		lot.Quantity -= take
+		lot.Version++
+		if err := s.lotRepo.UpdateWithTx(tx, lot); err != nil {
+			return nil, fmt.Errorf("failed to update lot: %w", err)
+		}
+
+		allocations = append(allocations, model.RequestLot{
+			ID:         uuid.New(),
+			RequestID:  req.ID,
+			LineID:     line.ID,
+			ShipmentID: shipmentID,
+			LotID:      lot.ID,
+			Quantity:   take,
+			Lot:        lot,
+		})
+		remaining -= take
+	}
+
+	if remaining > 0 {
+		// Lots never hold more than the balance, so the picked balance must still cover
+		// every unit left in a lot, expired ones included
+		inLots, err := s.lotRepo.SumQuantityWithTx(tx, stock.ItemID, stock.WarehouseID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read lot balances: %w", err)
+		}
+		if stock.Quantity < inLots {
+			outsideLots := max(stock.Quantity-inLots+remaining, 0)
+			return nil, fmt.Errorf("insufficient stock: %d in unexpired lots and %d outside lots, requested %d", quantity-remaining, outsideLots, quantity)
+		}
+	}
+
+	if err := s.lotRepo.CreateAllocationsWithTx(tx, allocations); err != nil {
+		return nil, fmt.Errorf("failed to record lot allocation: %w", err)
+	}
+	return allocations, nil
+}
+
+// validateSerials checks the serial numbers named on a new request line. Serialized items
+// need exactly quantity unique serials; inbound serials must not already be in stock, while
+// outbound and transfer serials must be in stock at the request's (source) warehouse.
+func (s *RequestService) validateSerials(item *model.Inventory, reqType string, warehouseID uuid.UUID, quantity int, raw []string) ([]model.RequestSerial, error) {
+	if !item.Serialized {
+		if len(raw) > 0 {
+			return nil, errors.New("item is not serialized; serial_numbers must be empty")
+		}
+		return nil, nil
+	}
+
+	if len(raw) != quantity {
+		return nil, fmt.Errorf("serialized item needs exactly %d serial numbers, got %d", quantity, len(raw))
+	}
+
+	seen := make(map[string]bool, len(raw))
+	serials := make([]string, 0, len(raw))
+	for _, serial := range raw {
+		serial = strings.TrimSpace(serial)
+		if serial == "" {
+			return nil, errors.New("serial numbers cannot be blank")
+		}
+		if seen[serial] {
+			return nil, fmt.Errorf("duplicate serial number: %s", serial)
+		}
+		seen[serial] = true
+		serials = append(serials, serial)
+	}
+
+	units, err := s.serialRepo.FindByItemAndSerials(item.ID, serials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up serial numbers: %w", err)
+	}
+	known := make(map[string]model.SerialNumber, len(units))
+	for _, unit := range units {
+		known[unit.SerialNumber] = unit
+	}
+
+	requestSerials := make([]model.RequestSerial, 0, len(serials))
+	for _, serial := range serials {
+		unit, exists := known[serial]
+		if reqType == model.RequestTypeInbound {
+			if exists && unit.Status == model.SerialStatusInStock {
+				return nil, fmt.Errorf("serial number %s is already in stock", serial)
+			}
+		} else {
+			if !exists {
+				return nil, fmt.Errorf("unknown serial number: %s", serial)
+			}
+			if unit.Status != model.SerialStatusInStock || unit.WarehouseID != warehouseID {
+				return nil, fmt.Errorf("serial number %s is not in stock at this warehouse", serial)
+			}
+		}
+		requestSerials = append(requestSerials, model.RequestSerial{ID: uuid.New(), SerialNumber: serial})
+	}
+	return requestSerials, nil
+}
+
+// moveSerials applies a request's serial numbers inside the approval transaction,
+// line by line
+func (s *RequestService) moveSerials(tx *gorm.DB, req *model.Request, approverID uuid.UUID) error {
+	if len(req.Serials) == 0 {
+		return nil
+	}
+
+	byLine := make(map[uuid.UUID][]string, len(req.Lines))
+	for _, requestSerial := range req.Serials {
+		byLine[requestSerial.LineID] = append(byLine[requestSerial.LineID], requestSerial.SerialNumber)
+	}
+
+	for i := range req.Lines {
+		line := &req.Lines[i]
+		if serials := byLine[line.ID]; len(serials) > 0 {
+			if err := s.moveLineSerials(tx, req, line.ItemID, serials, approverID); err != nil {
+				return lineError(req, line, err)
+			}
+		}
+	}
+	return nil
+}
+
+// moveLineSerials moves the serial numbers of one item: inbound units are received,
+// outbound units shipped and transferred units moved to the destination. Every unit
+// gets a movement entry.
+func (s *RequestService) moveLineSerials(tx *gorm.DB, req *model.Request, itemID uuid.UUID, serials []string, approverID uuid.UUID) error {
+	units, err := s.serialRepo.FindForUpdate(tx, itemID, serials)
+	if err != nil {
+		return fmt.Errorf("failed to lock serial numbers: %w", err)
+	}
+	byNumber := make(map[string]*model.SerialNumber, len(units))
+	for i := range units {
+		byNumber[units[i].SerialNumber] = &units[i]
+	}
+
+	movements := make([]model.SerialMovement, 0, len(serials))
+	for _, serial := range serials {
+		unit, exists := byNumber[serial]
+		movement := model.SerialMovement{
+			ID:        uuid.New(),
+			RequestID: req.ID,
+			UserID:    approverID,
+		}
+
+		if req.Type == model.RequestTypeInbound {
+			if exists && unit.Status == model.SerialStatusInStock {
+				return fmt.Errorf("serial number %s is already in stock", serial)
+			}
+			if !exists {
+				unit = &model.SerialNumber{ID: uuid.New(), ItemID: itemID, SerialNumber: serial}
+			}
+			unit.WarehouseID = req.WarehouseID
+			unit.Status = model.SerialStatusInStock
+			movement.Type = model.SerialMovementReceived
+			movement.ToWarehouseID = &req.WarehouseID
+		} else {
+			if !exists || unit.Status != model.SerialStatusInStock || unit.WarehouseID != req.WarehouseID {
+				return fmt.Errorf("serial number %s is not in stock at this warehouse", serial)
+			}
+			movement.FromWarehouseID = &req.WarehouseID
+			if req.Type == model.RequestTypeTransfer {
+				unit.WarehouseID = *req.DestinationWarehouseID
+				movement.Type = model.SerialMovementTransferred
+				movement.ToWarehouseID = req.DestinationWarehouseID
+			} else {
+				unit.Status = model.SerialStatusShipped
+				movement.Type = model.SerialMovementShipped
+			}
+		}
+
+		if err := s.serialRepo.SaveWithTx(tx, unit); err != nil {
+			return fmt.Errorf("failed to update serial number: %w", err)
+		}
+		movement.SerialID = unit.ID
+		movements = append(movements, movement)
+	}
+
+	if err := s.serialRepo.CreateMovementsWithTx(tx, movements); err != nil {
+		return fmt.Errorf("failed to record serial movements: %w", err)
+	}
+	return nil
+}
+
+// resolveBin validates an optional bin ID against the warehouse it must belong to
+func (s *RequestService) resolveBin(raw string, warehouseID uuid.UUID) (*uuid.UUID, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	binID, err := uuid.Parse(raw)
+	if err != nil {
+		return nil, errors.New("invalid bin ID")
+	}
+	location, err := s.locationRepo.FindByID(binID)
+	if err != nil || location.WarehouseID != warehouseID {
+		return nil, errors.New("bin not found in request warehouse")
+	}
+	if location.Type != model.LocationTypeBin {
+		return nil, fmt.Errorf("location %s is a %s, not a bin", location.Path, location.Type)
+	}
+	return &location.ID, nil
+}
+
+// stockKey identifies one item/warehouse balance
+type stockKey struct {
+	itemID      uuid.UUID
+	warehouseID uuid.UUID
+}
+
+func (k stockKey) lockKey() string {
+	return infrastructure.StockLockKey(k.itemID, k.warehouseID)
+}
+
+// lineStockKeys returns the balances of every line's item at one warehouse
+func lineStockKeys(req *model.Request, warehouseID uuid.UUID) []stockKey {
+	keys := make([]stockKey, 0, len(req.Lines))
+	for _, line := range req.Lines {
+		keys = append(keys, stockKey{itemID: line.ItemID, warehouseID: warehouseID})
+	}
+	return keys
+}
+
+// reservedStockKeys returns the source balances the request's lines still hold stock on
+func reservedStockKeys(req *model.Request) []stockKey {
+	var keys []stockKey
+	for _, line := range req.Lines {
+		if line.ReservedQuantity > 0 {
+			keys = append(keys, stockKey{itemID: line.ItemID, warehouseID: req.WarehouseID})
+		}
+	}
+	return keys
+}
+
+// sortedStockKeys removes duplicate balances and orders the rest by lock key, the
+// order in which both distributed locks and row locks are taken
+func sortedStockKeys(keys []stockKey) []stockKey {
+	seen := make(map[stockKey]bool, len(keys))
+	sorted := make([]stockKey, 0, len(keys))
+	for _, key := range keys {
+		if !seen[key] {
+			seen[key] = true
+			sorted = append(sorted, key)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].lockKey() < sorted[j].lockKey()
+	})
+	return sorted
+}
+
+// lockBalances takes the distributed locks of the given balances
+func (s *RequestService) lockBalances(ctx context.Context, keys []stockKey) (*heldLocks, error) {
+	lockKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		lockKeys = append(lockKeys, key.lockKey())
+	}
+	return s.acquireLocks(ctx, lockKeys...)
+}
+
+// lockStocks locks the given balances with SELECT FOR UPDATE in key order and fences
+// them with the lease in locks. Balances for which create returns true are
+// created when missing; other missing balances are left out of the result. Inbound
+// approval passes nil locks; it adds stock and relies on the row locks alone.
+func (s *RequestService) lockStocks(tx *gorm.DB, locks *heldLocks, keys []stockKey, create func(stockKey) bool) (map[stockKey]*model.InventoryStock, error) {
+	stocks := make(map[stockKey]*model.InventoryStock, len(keys))
+	for _, key := range sortedStockKeys(keys) {
+		var stock *model.InventoryStock
+		var err error
+		if create != nil && create(key) {
+			stock, err = s.stockRepo.FindOrCreateForUpdate(tx, key.itemID, key.warehouseID)
+		} else {
+			stock, err = s.stockRepo.FindForUpdate(tx, key.itemID, key.warehouseID)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock stock balance: %w", err)
+		}
+
+		if err := locks.fence(key, stock); err != nil {
+			return nil, err
+		}
+		stocks[key] = stock
+	}
+	return stocks, nil
+}
+
+// saveStocks writes back locked balances whose reservations changed
+func (s *RequestService) saveStocks(tx *gorm.DB, keys []stockKey, stocks map[stockKey]*model.InventoryStock) error {
+	for _, key := range sortedStockKeys(keys) {
+		stock := stocks[key]
+		if stock == nil {
+			continue
+		}
+		stock.Version++
+		if err := s.stockRepo.UpdateWithTx(tx, stock); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lineError prefixes an error with the line number on multi-line requests, so
+// single-item requests keep their error messages
+func lineError(req *model.Request, line *model.RequestLine, err error) error {
+	if len(req.Lines) <= 1 {
+		return err
+	}
+	return fmt.Errorf("line %d: %w", line.LineNo, err)
+}
+
+// heldLocks is the lease a request operation holds on its balances
+type heldLocks struct {
+	lease *infrastructure.Lease
+}
+
+// release releases the lease. It does not use the request context, which may
+// already be cancelled when the operation returns.
+func (l *heldLocks) release() {
+	_ = l.lease.Release(context.Background())
+}
+
+// fence refuses to write a balance when the lease guarding it was lost, or when a
+// later holder of the lock has already written it (its fencing token is larger),
+// then stamps the balance with this lease's token. nil locks do not fence.
+func (l *heldLocks) fence(key stockKey, stock *model.InventoryStock) error {
+	if l == nil {
+		return nil
+	}
+	token, ok := l.lease.Token(key.lockKey())
+	if !ok {
+		return nil
+	}
+	if err := l.check(); err != nil {
+		return err
+	}
+	if stock.FenceToken > token {
+		return fmt.Errorf("lock conflict: balance was written by a later lock holder")
+	}
+	stock.FenceToken = token
+	return nil
+}
+
+// check is the last step of a locked transaction: it refuses to commit when the
+// lease was lost during the writes. nil locks do not check.
+func (l *heldLocks) check() error {
+	if l == nil {
+		return nil
+	}
+	if err := l.lease.Check(); err != nil {
+		return fmt.Errorf("lock conflict: %w", err)
+	}
+	return nil
+}
+
+// acquireLocks takes several locks at once; the locker orders them so concurrent
+// operations cannot deadlock
+func (s *RequestService) acquireLocks(ctx context.Context, keys ...string) (*heldLocks, error) {
+	lease, err := s.locker.Acquire(ctx, keys...)
+	if err != nil {
+		return nil, fmt.Errorf("lock conflict: %w", err)
+	}
+	return &heldLocks{lease: lease}, nil
+}
+
+// RejectRequest rejects a pending request with the approver's own authority or one
+// delegated to them, releasing its reservation and recording the reason
+func (s *RequestService) RejectRequest(ctx context.Context, requestID uuid.UUID, approverID uuid.UUID, approverRole string, input dto.RejectRequestInput) (*model.Request, error) {
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" {
+		return nil, errors.New("rejection reason is required")
+	}
+
+	req, err := s.requestRepo.FindByID(requestID)
+	if err != nil {
+		return nil, errors.New("request not found")
+	}
+
+	signer, err := s.resolveApprover(req, nil, approverID, approverRole)
+	if err != nil {
+		return nil, err
+	}
+
+	if !model.ValidTransition(req.Status, model.StatusRejected) {
+		return nil, fmt.Errorf("cannot reject request with status: %s", req.Status)
+	}
+
+	// Releasing a reservation changes the balances, so it runs under the same locks as approval
+	locks, err := s.lockBalances(ctx, reservedStockKeys(req))
+	if err != nil {
+		return nil, err
+	}
+	defer locks.release()
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.lockRequest(tx, req, model.StatusRejected); err != nil {
+			return err
+		}
+
+		beforeJSON, _ := json.Marshal(req)
+
+		if err := s.releaseReservation(tx, locks, req); err != nil {
+			return err
+		}
+
+		req.Status = model.StatusRejected
+		req.ApprovedBy = &approverID
+		req.RejectionReason = reason
+
+		if err := s.requestRepo.UpdateWithTx(tx, req); err != nil {
+			return fmt.Errorf("failed to reject request: %w", err)
+		}
+
+		afterJSON, _ := json.Marshal(map[string]interface{}{
+			"request":      req,
+			"reason":       reason,
+			"on_behalf_of": signer.onBehalfOf,
+		})
+		if err := s.auditRepo.CreateWithTx(tx, &model.AuditLog{
+			ID:          uuid.New(),
+			Entity:      "request",
+			EntityID:    req.ID,
+			Action:      "REJECTED",
+			UserID:      approverID,
+			BeforeValue: beforeJSON,
+			AfterValue:  afterJSON,
+		}); err != nil {
+			return fmt.Errorf("failed to create audit log: %w", err)
+		}
+
+		return locks.check()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Request rejected",
+		zap.String("request_id", req.ID.String()),
+		zap.String("approver_id", approverID.String()),
+	)
+
+	return s.requestRepo.FindByID(req.ID)
+}
+
+// BulkDecide approves or rejects each listed request as ApproveRequest or
+// RejectRequest would, each in its own transaction. A failure is reported for its
+// request and does not stop the others; a repeated ID is decided once.
+func (s *RequestService) BulkDecide(ctx context.Context, input dto.BulkDecisionInput, approverID uuid.UUID, approverRole string) []dto.BulkDecisionResult {
+	results := make([]dto.BulkDecisionResult, 0, len(input.RequestIDs))
+	seen := make(map[uuid.UUID]bool, len(input.RequestIDs))
+
+	for _, rawID := range input.RequestIDs {
+		result := dto.BulkDecisionResult{RequestID: rawID}
+
+		requestID, err := uuid.Parse(rawID)
+		if err != nil {
+			result.Error = "invalid request ID"
+			results = append(results, result)
+			continue
+		}
+		if seen[requestID] {
+			continue
+		}
+		seen[requestID] = true
+
+		var req *model.Request
+		if input.Decision == dto.DecisionReject {
+			req, err = s.RejectRequest(ctx, requestID, approverID, approverRole, dto.RejectRequestInput{Reason: input.Reason})
+		} else {
+			req, err = s.ApproveRequest(ctx, requestID, approverID, approverRole, dto.ApproveRequestInput{})
+		}
+
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Success = true
+			result.Status = req.Status
+		}
+		results = append(results, result)
+	}
+
+	succeeded := 0
+	for _, result := range results {
+		if result.Success {
+			succeeded++
+		}
+	}
+	s.log.Info("Bulk request decision",
+		zap.String("decision", input.Decision),
+		zap.String("approver_id", approverID.String()),
+		zap.Int("requested", len(results)),
+		zap.Int("succeeded", succeeded),
+	)
+
+	return results
+}
+
+// CancelRequest withdraws a request that has not completed. Only its creator or an
+// admin may cancel it; whatever the request still reserves is released, and the
+// reason is kept in the audit log.
+func (s *RequestService) CancelRequest(ctx context.Context, requestID uuid.UUID, userID uuid.UUID, userRole string, input dto.CancelRequestInput) (*model.Request, error) {
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" {
+		return nil, errors.New("cancellation reason is required")
+	}
+
+	req, err := s.requestRepo.FindByID(requestID)
+	if err != nil {
+		return nil, errors.New("request not found")
+	}
+
+	if req.CreatedBy != userID && userRole != model.RoleAdmin {
+		return nil, errors.New("insufficient permissions to cancel this request")
+	}
+
+	if !model.ValidTransition(req.Status, model.StatusCancelled) {
+		return nil, fmt.Errorf("cannot cancel request with status: %s", req.Status)
+	}
+
+	locks, err := s.lockBalances(ctx, reservedStockKeys(req))
+	if err != nil {
+		return nil, err
+	}
+	defer locks.release()
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.lockRequest(tx, req, model.StatusCancelled); err != nil {
+			return err
+		}
+
+		beforeJSON, _ := json.Marshal(req)
+
+		if err := s.releaseReservation(tx, locks, req); err != nil {
+			return err
+		}
+
+		req.Status = model.StatusCancelled
+
+		if err := s.requestRepo.UpdateWithTx(tx, req); err != nil {
+			return fmt.Errorf("failed to cancel request: %w", err)
+		}
+
+		afterJSON, _ := json.Marshal(map[string]interface{}{
+			"request": req,
+			"reason":  reason,
+		})
+		if err := s.auditRepo.CreateWithTx(tx, &model.AuditLog{
+			ID:          uuid.New(),
+			Entity:      "request",
+			EntityID:    req.ID,
+			Action:      "CANCELLED",
+			UserID:      userID,
+			BeforeValue: beforeJSON,
+			AfterValue:  afterJSON,
+		}); err != nil {
+			return fmt.Errorf("failed to create audit log: %w", err)
+		}
+
+		return locks.check()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Request cancelled",
+		zap.String("request_id", req.ID.String()),
+		zap.String("user_id", userID.String()),
+	)
+
+	return s.requestRepo.FindByID(req.ID)
+}
+
+// parseWarehouseID validates that the warehouse referenced by a request exists
+func (s *RequestService) parseWarehouseID(raw string) (uuid.UUID, error) {
+	warehouseID, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, errors.New("invalid warehouse ID")
+	}
+	if _, err := s.warehouseRepo.FindByID(warehouseID); err != nil {
+		return uuid.Nil, errors.New("warehouse not found")
+	}
+	return warehouseID, nil
+}
+
 func (s *RequestService) GetByID(id uuid.UUID) (*model.Request, error) {
 	return s.requestRepo.FindByID(id)
 }
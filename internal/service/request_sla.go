@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/config"
+	"github.com/senoagung27/warehousex/internal/infrastructure"
+	"github.com/senoagung27/warehousex/internal/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// slaBatchSize caps the requests escalated or expired per request type and run
+const slaBatchSize = 100
+
+// ProcessSLAs expires pending requests past their type's expiry SLA and escalates
+// those past the escalation SLA. A failure on one request is logged and does not
+// stop the others.
+func (s *RequestService) ProcessSLAs(ctx context.Context, slas map[string]config.SLAConfig, now time.Time) error {
+	for reqType, sla := range slas {
+		if sla.ExpireAfter > 0 {
+			stale, err := s.requestRepo.FindPendingBefore(reqType, now.Add(-sla.ExpireAfter), false, slaBatchSize)
+			if err != nil {
+				return fmt.Errorf("failed to find expired requests: %w", err)
+			}
+			for i := range stale {
+				if err := s.expireRequest(ctx, &stale[i]); err != nil {
+					s.log.Warn("Failed to expire request",
+						zap.String("request_id", stale[i].ID.String()),
+						zap.Error(err),
+					)
+				}
+			}
+		}
+
+		if sla.EscalateAfter > 0 {
+			stale, err := s.requestRepo.FindPendingBefore(reqType, now.Add(-sla.EscalateAfter), true, slaBatchSize)
+			if err != nil {
+				return fmt.Errorf("failed to find requests to escalate: %w", err)
+			}
+			for i := range stale {
+				if err := s.escalateRequest(ctx, &stale[i], now); err != nil {
+					s.log.Warn("Failed to escalate request",
+						zap.String("request_id", stale[i].ID.String()),
+						zap.Error(err),
+					)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// escalateRequest notifies the approver tier above the request's next approval
+// step, or above supervisors when it has no steps. Each request is escalated once;
+// one decided or escalated since it was read is skipped. A step already waiting for
+// the top tier has no one to escalate to, so it is only marked. The notification
+// goes out after the escalation is committed.
+func (s *RequestService) escalateRequest(ctx context.Context, req *model.Request, now time.Time) error {
+	waitingFor := model.RoleSupervisor
+	if step := req.NextApproval(); step != nil {
+		waitingFor = step.Role
+	}
+	tier, hasTier := model.NextApprovalTier(waitingFor)
+
+	var recipients []uuid.UUID
+	if hasTier {
+		users, err := s.userRepo.FindByRole(tier)
+		if err != nil {
+			return fmt.Errorf("failed to find %s users: %w", tier, err)
+		}
+		recipients = make([]uuid.UUID, len(users))
+		for i, user := range users {
+			recipients[i] = user.ID
+		}
+	}
+
+	escalated := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		marked, err := s.requestRepo.MarkEscalatedWithTx(tx, req.ID, now)
+		if err != nil {
+			return fmt.Errorf("failed to update request: %w", err)
+		}
+		if !marked {
+			return nil
+		}
+
+		afterJSON, _ := json.Marshal(map[string]interface{}{
+			"waiting_for":  waitingFor,
+			"escalated_to": tier,
+			"recipients":   recipients,
+		})
+		if err := s.auditRepo.CreateWithTx(tx, &model.AuditLog{
+			ID:         uuid.New(),
+			Entity:     "request",
+			EntityID:   req.ID,
+			Action:     "ESCALATED",
+			UserID:     model.SystemUserID,
+			AfterValue: afterJSON,
+		}); err != nil {
+			return fmt.Errorf("failed to create audit log: %w", err)
+		}
+
+		escalated = true
+		return nil
+	})
+	if err != nil || !escalated {
+		return err
+	}
+
+	req.EscalatedAt = &now
+
+	if !hasTier {
+		s.log.Info("Request overdue with no approver tier to escalate to",
+			zap.String("request_id", req.ID.String()),
+			zap.String("waiting_for", waitingFor),
+		)
+		return nil
+	}
+
+	if err := s.notifier.Notify(ctx, infrastructure.Notification{
+		UserIDs: recipients,
+		Subject: fmt.Sprintf("%s request %s awaiting approval", req.Type, req.ID),
+		Body:    fmt.Sprintf("Request %s has been pending since %s.", req.ID, req.CreatedAt.Format(time.RFC3339)),
+	}); err != nil {
+		return fmt.Errorf("failed to notify %s users: %w", tier, err)
+	}
+
+	s.log.Info("Request escalated",
+		zap.String("request_id", req.ID.String()),
+		zap.String("tier", tier),
+	)
+	return nil
+}
+
+// expireRequest moves a request pending past its SLA to EXPIRED and releases its reservation
+func (s *RequestService) expireRequest(ctx context.Context, req *model.Request) error {
+	locks, err := s.lockBalances(ctx, reservedStockKeys(req))
+	if err != nil {
+		return err
+	}
+	defer locks.release()
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.lockRequest(tx, req, model.StatusExpired); err != nil {
+			return err
+		}
+
+		if err := s.releaseReservation(tx, locks, req); err != nil {
+			return err
+		}
+
+		req.Status = model.StatusExpired
+
+		if err := s.requestRepo.UpdateWithTx(tx, req); err != nil {
+			return fmt.Errorf("failed to expire request: %w", err)
+		}
+
+		afterJSON, _ := json.Marshal(req)
+		if err := s.auditRepo.CreateWithTx(tx, &model.AuditLog{
+			ID:         uuid.New(),
+			Entity:     "request",
+			EntityID:   req.ID,
+			Action:     "EXPIRED",
+			UserID:     model.SystemUserID,
+			AfterValue: afterJSON,
+		}); err != nil {
+			return fmt.Errorf("failed to create audit log: %w", err)
+		}
+
+		return locks.check()
+	})
+	if err != nil {
+		return err
+	}
+
+	s.log.Info("Request expired",
+		zap.String("request_id", req.ID.String()),
+	)
+	return nil
+}
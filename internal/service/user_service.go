@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/config"
+	"github.com/senoagung27/warehousex/internal/domain/repository"
+	"github.com/senoagung27/warehousex/internal/dto"
+	"github.com/senoagung27/warehousex/internal/model"
+	"go.uber.org/zap"
+)
+
+var _ UserServiceInterface = (*UserService)(nil)
+
+// accountAuth is the part of AuthService user administration acts through
+type accountAuth interface {
+	RevokeSessions(ctx context.Context, userID uuid.UUID) error
+	ResetLoginFailures(ctx context.Context, email string) error
+}
+
+type UserService struct {
+	userRepo  repository.UserRepository
+	auditRepo repository.AuditLogRepository
+	auth      accountAuth
+	authCfg   config.AuthConfig
+	log       *zap.Logger
+}
+
+func NewUserService(
+	userRepo repository.UserRepository,
+	auditRepo repository.AuditLogRepository,
+	auth accountAuth,
+	authCfg config.AuthConfig,
+	log *zap.Logger,
+) *UserService {
+	return &UserService{
+		userRepo:  userRepo,
+		auditRepo: auditRepo,
+		auth:      auth,
+		authCfg:   authCfg,
+		log:       log,
+	}
+}
+
+// GetAll returns a page of users whose name or email contains search, filtered by
+// role and status when they are set
+func (s *UserService) GetAll(page, limit int, search, role, status string) ([]model.User, int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	return s.userRepo.FindAll(page, limit, search, role, status)
+}
+
+func (s *UserService) GetByID(id uuid.UUID) (*model.User, error) {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
+
+// UpdateRole changes a user's role. It takes effect on the user's next request.
+func (s *UserService) UpdateRole(id uuid.UUID, input dto.UpdateUserRoleInput, userID uuid.UUID) (*model.User, error) {
+	if id == userID {
+		return nil, errors.New("cannot change your own role")
+	}
+	if id == model.SystemUserID {
+		return nil, errors.New("cannot change the system user's role")
+	}
+
+	user, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == input.Role {
+		return user, nil
+	}
+	if user.Role == model.RoleAdmin {
+		if err := s.ensureAnotherAdmin(user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.update(user, userID, "UPDATE_ROLE", func(u *model.User) {
+		u.Role = input.Role
+	})
+}
+
+// Activate lets a self-registered or deactivated user log in again
+func (s *UserService) Activate(id uuid.UUID, userID uuid.UUID) (*model.User, error) {
+	if id == model.SystemUserID {
+		return nil, errors.New("cannot activate the system user")
+	}
+
+	user, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Status == model.UserStatusActive {
+		return user, nil
+	}
+
+	return s.update(user, userID, "ACTIVATE", func(u *model.User) {
+		u.Status = model.UserStatusActive
+	})
+}
+
+// Deactivate blocks a user from logging in and ends their sessions; tokens they
+// hold stop working at once
+func (s *UserService) Deactivate(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*model.User, error) {
+	if id == userID {
+		return nil, errors.New("cannot deactivate yourself")
+	}
+	if id == model.SystemUserID {
+		return nil, errors.New("cannot deactivate the system user")
+	}
+
+	user, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Status == model.UserStatusDeactivated {
+		return user, nil
+	}
+	if user.Role == model.RoleAdmin {
+		if err := s.ensureAnotherAdmin(user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	user, err = s.update(user, userID, "DEACTIVATE", func(u *model.User) {
+		u.Status = model.UserStatusDeactivated
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.revokeSessions(ctx, user.ID)
+	return user, nil
+}
+
+// Unlock lifts a lockout after failed logins and forgets the failures
+func (s *UserService) Unlock(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*model.User, error) {
+	user, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.auth.ResetLoginFailures(ctx, user.Email); err != nil {
+		return nil, err
+	}
+	if user.LockedUntil == nil {
+		return user, nil
+	}
+
+	return s.update(user, userID, "UNLOCK", func(u *model.User) {
+		u.LockedUntil = nil
+	})
+}
+
+// ForcePasswordReset ends the user's sessions and blocks their login until they set
+// a new password with the returned one-time token
+func (s *UserService) ForcePasswordReset(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dto.PasswordResetResponse, error) {
+	if id == model.SystemUserID {
+		return nil, errors.New("cannot reset the system user's password")
+	}
+
+	user, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	token, tokenHash, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(s.authCfg.ResetTTL)
+
+	user, err = s.update(user, userID, "FORCE_PASSWORD_RESET", func(u *model.User) {
+		u.PasswordResetRequired = true
+		u.ResetTokenHash = &tokenHash
+		u.ResetTokenExpiresAt = &expiresAt
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.revokeSessions(ctx, user.ID)
+
+	return &dto.PasswordResetResponse{User: *user, Token: token}, nil
+}
+
+// update applies change to the user and audits the before and after values
+func (s *UserService) update(user *model.User, userID uuid.UUID, action string, change func(*model.User)) (*model.User, error) {
+	beforeJSON, _ := json.Marshal(user)
+	change(user)
+
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	afterJSON, _ := json.Marshal(user)
+	_ = s.auditRepo.Create(&model.AuditLog{
+		ID:          uuid.New(),
+		Entity:      "user",
+		EntityID:    user.ID,
+		Action:      action,
+		UserID:      userID,
+		BeforeValue: beforeJSON,
+		AfterValue:  afterJSON,
+	})
+
+	s.log.Info("User updated",
+		zap.String("user_id", user.ID.String()),
+		zap.String("action", action),
+		zap.String("by", userID.String()),
+	)
+
+	return user, nil
+}
+
+// revokeSessions ends the user's sessions; JWTAuth already refuses the account, so
+// a failure is only logged
+func (s *UserService) revokeSessions(ctx context.Context, userID uuid.UUID) {
+	if err := s.auth.RevokeSessions(ctx, userID); err != nil {
+		s.log.Error("Failed to revoke user sessions", zap.String("user_id", userID.String()), zap.Error(err))
+	}
+}
+
+// ensureAnotherAdmin refuses to demote or deactivate the last active admin
+func (s *UserService) ensureAnotherAdmin(adminID uuid.UUID) error {
+	admins, err := s.userRepo.FindByRole(model.RoleAdmin)
+	if err != nil {
+		return fmt.Errorf("failed to look up admins: %w", err)
+	}
+	for _, admin := range admins {
+		if admin.ID != adminID && admin.Status == model.UserStatusActive {
+			return nil
+		}
+	}
+	return errors.New("cannot remove the last active admin")
+}
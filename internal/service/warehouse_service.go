@@ -0,0 +1,205 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/senoagung27/warehousex/internal/domain/repository"
+	"github.com/senoagung27/warehousex/internal/dto"
+	"github.com/senoagung27/warehousex/internal/model"
+	"go.uber.org/zap"
+)
+
+var _ WarehouseServiceInterface = (*WarehouseService)(nil)
+
+type WarehouseService struct {
+	warehouseRepo repository.WarehouseRepository
+	locationRepo  repository.LocationRepository
+	auditRepo     repository.AuditLogRepository
+	log           *zap.Logger
+}
+
+func NewWarehouseService(
+	warehouseRepo repository.WarehouseRepository,
+	locationRepo repository.LocationRepository,
+	auditRepo repository.AuditLogRepository,
+	log *zap.Logger,
+) *WarehouseService {
+	return &WarehouseService{
+		warehouseRepo: warehouseRepo,
+		locationRepo:  locationRepo,
+		auditRepo:     auditRepo,
+		log:           log,
+	}
+}
+
+func (s *WarehouseService) Create(input dto.CreateWarehouseInput, userID uuid.UUID) (*model.Warehouse, error) {
+	existing, _ := s.warehouseRepo.FindByCode(input.Code)
+	if existing != nil {
+		return nil, errors.New("warehouse code already exists")
+	}
+
+	warehouse := &model.Warehouse{
+		ID:      uuid.New(),
+		Code:    input.Code,
+		Name:    input.Name,
+		Address: input.Address,
+	}
+
+	if err := s.warehouseRepo.Create(warehouse); err != nil {
+		return nil, fmt.Errorf("failed to create warehouse: %w", err)
+	}
+
+	afterJSON, _ := json.Marshal(warehouse)
+	_ = s.auditRepo.Create(&model.AuditLog{
+		ID:         uuid.New(),
+		Entity:     "warehouse",
+		EntityID:   warehouse.ID,
+		Action:     "CREATE",
+		UserID:     userID,
+		AfterValue: afterJSON,
+	})
+
+	s.log.Info("Warehouse created",
+		zap.String("warehouse_id", warehouse.ID.String()),
+		zap.String("code", warehouse.Code),
+	)
+
+	return warehouse, nil
+}
+
+func (s *WarehouseService) GetByID(id uuid.UUID) (*model.Warehouse, error) {
+	return s.warehouseRepo.FindByID(id)
+}
+
+func (s *WarehouseService) GetAll(page, limit int) ([]model.Warehouse, int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	return s.warehouseRepo.FindAll(page, limit)
+}
+
+func (s *WarehouseService) Update(id uuid.UUID, input dto.UpdateWarehouseInput, userID uuid.UUID) (*model.Warehouse, error) {
+	warehouse, err := s.warehouseRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("warehouse not found: %w", err)
+	}
+
+	beforeJSON, _ := json.Marshal(warehouse)
+
+	if input.Code != "" && input.Code != warehouse.Code {
+		existing, _ := s.warehouseRepo.FindByCode(input.Code)
+		if existing != nil {
+			return nil, errors.New("warehouse code already exists")
+		}
+		warehouse.Code = input.Code
+	}
+	if input.Name != "" {
+		warehouse.Name = input.Name
+	}
+	if input.Address != "" {
+		warehouse.Address = input.Address
+	}
+
+	if err := s.warehouseRepo.Update(warehouse); err != nil {
+		return nil, fmt.Errorf("failed to update warehouse: %w", err)
+	}
+
+	afterJSON, _ := json.Marshal(warehouse)
+	_ = s.auditRepo.Create(&model.AuditLog{
+		ID:          uuid.New(),
+		Entity:      "warehouse",
+		EntityID:    warehouse.ID,
+		Action:      "UPDATE",
+		UserID:      userID,
+		BeforeValue: beforeJSON,
+		AfterValue:  afterJSON,
+	})
+
+	s.log.Info("Warehouse updated",
+		zap.String("warehouse_id", warehouse.ID.String()),
+	)
+
+	return warehouse, nil
+}
+
+func (s *WarehouseService) CreateLocation(warehouseID uuid.UUID, input dto.CreateLocationInput, userID uuid.UUID) (*model.Location, error) {
+	if _, err := s.warehouseRepo.FindByID(warehouseID); err != nil {
+		return nil, errors.New("warehouse not found")
+	}
+
+	parentType, ok := model.ParentLocationType(input.Type)
+	if !ok {
+		return nil, fmt.Errorf("invalid location type: %s", input.Type)
+	}
+
+	location := &model.Location{
+		ID:          uuid.New(),
+		WarehouseID: warehouseID,
+		Type:        input.Type,
+		Code:        input.Code,
+		Path:        input.Code,
+		Name:        input.Name,
+	}
+
+	if parentType == "" {
+		if input.ParentID != "" {
+			return nil, errors.New("a zone cannot have a parent location")
+		}
+	} else {
+		if input.ParentID == "" {
+			return nil, fmt.Errorf("a %s must be placed inside a %s", input.Type, parentType)
+		}
+		parentID, err := uuid.Parse(input.ParentID)
+		if err != nil {
+			return nil, errors.New("invalid parent location ID")
+		}
+		parent, err := s.locationRepo.FindByID(parentID)
+		if err != nil || parent.WarehouseID != warehouseID {
+			return nil, errors.New("parent location not found in this warehouse")
+		}
+		if parent.Type != parentType {
+			return nil, fmt.Errorf("a %s must be placed inside a %s, not a %s", input.Type, parentType, parent.Type)
+		}
+		location.ParentID = &parent.ID
+		location.Path = parent.Path + "/" + input.Code
+	}
+
+	existing, _ := s.locationRepo.FindByPath(warehouseID, location.Path)
+	if existing != nil {
+		return nil, fmt.Errorf("location %s already exists", location.Path)
+	}
+
+	if err := s.locationRepo.Create(location); err != nil {
+		return nil, fmt.Errorf("failed to create location: %w", err)
+	}
+
+	afterJSON, _ := json.Marshal(location)
+	_ = s.auditRepo.Create(&model.AuditLog{
+		ID:         uuid.New(),
+		Entity:     "location",
+		EntityID:   location.ID,
+		Action:     "CREATE",
+		UserID:     userID,
+		AfterValue: afterJSON,
+	})
+
+	s.log.Info("Location created",
+		zap.String("warehouse_id", warehouseID.String()),
+		zap.String("path", location.Path),
+	)
+
+	return location, nil
+}
+
+func (s *WarehouseService) GetLocations(warehouseID uuid.UUID, locationType string) ([]model.Location, error) {
+	if _, err := s.warehouseRepo.FindByID(warehouseID); err != nil {
+		return nil, errors.New("warehouse not found")
+	}
+	return s.locationRepo.FindByWarehouse(warehouseID, locationType)
+}
@@ -0,0 +1,9 @@
+ALTER TABLE requests DROP COLUMN IF EXISTS warehouse_id;
+
+ALTER TABLE inventory ADD COLUMN quantity INT NOT NULL DEFAULT 0 CHECK (quantity >= 0);
+UPDATE inventory i SET quantity = COALESCE(
+    (SELECT SUM(s.quantity) FROM inventory_stocks s WHERE s.item_id = i.id), 0
+);
+
+DROP TABLE IF EXISTS inventory_stocks;
+DROP TABLE IF EXISTS warehouses;
@@ -0,0 +1,40 @@
+-- Warehouses table
+CREATE TABLE warehouses (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    address TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Existing single-site stock moves into a default warehouse
+INSERT INTO warehouses (code, name) VALUES ('MAIN', 'Main Warehouse');
+
+-- Per-warehouse stock balances
+CREATE TABLE inventory_stocks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    item_id UUID NOT NULL REFERENCES inventory(id),
+    warehouse_id UUID NOT NULL REFERENCES warehouses(id),
+    quantity INT NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    version INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT idx_inventory_stocks_item_warehouse UNIQUE (item_id, warehouse_id)
+);
+
+INSERT INTO inventory_stocks (item_id, warehouse_id, quantity)
+SELECT i.id, w.id, i.quantity
+FROM inventory i CROSS JOIN warehouses w
+WHERE w.code = 'MAIN';
+
+ALTER TABLE inventory DROP COLUMN quantity;
+
+-- Requests target a single warehouse
+ALTER TABLE requests ADD COLUMN warehouse_id UUID REFERENCES warehouses(id);
+UPDATE requests SET warehouse_id = (SELECT id FROM warehouses WHERE code = 'MAIN');
+ALTER TABLE requests ALTER COLUMN warehouse_id SET NOT NULL;
+
+-- Indexes
+CREATE INDEX idx_inventory_stocks_warehouse_id ON inventory_stocks(warehouse_id);
+CREATE INDEX idx_requests_warehouse_id ON requests(warehouse_id);
@@ -0,0 +1,8 @@
+DELETE FROM requests WHERE type = 'TRANSFER';
+
+ALTER TABLE requests DROP CONSTRAINT IF EXISTS requests_transfer_destination_check;
+ALTER TABLE requests DROP COLUMN IF EXISTS destination_warehouse_id;
+
+ALTER TABLE requests DROP CONSTRAINT requests_type_check;
+ALTER TABLE requests ADD CONSTRAINT requests_type_check
+    CHECK (type IN ('INBOUND', 'OUTBOUND'));
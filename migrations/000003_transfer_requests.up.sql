@@ -0,0 +1,13 @@
+-- Inter-warehouse transfers: warehouse_id is the source, destination_warehouse_id the target
+ALTER TABLE requests DROP CONSTRAINT requests_type_check;
+ALTER TABLE requests ADD CONSTRAINT requests_type_check
+    CHECK (type IN ('INBOUND', 'OUTBOUND', 'TRANSFER'));
+
+ALTER TABLE requests ADD COLUMN destination_warehouse_id UUID REFERENCES warehouses(id);
+ALTER TABLE requests ADD CONSTRAINT requests_transfer_destination_check
+    CHECK (
+        (type = 'TRANSFER' AND destination_warehouse_id IS NOT NULL AND destination_warehouse_id <> warehouse_id)
+        OR (type <> 'TRANSFER' AND destination_warehouse_id IS NULL)
+    );
+
+CREATE INDEX idx_requests_destination_warehouse_id ON requests(destination_warehouse_id);
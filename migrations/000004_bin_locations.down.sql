@@ -0,0 +1,5 @@
+ALTER TABLE requests DROP COLUMN IF EXISTS destination_bin_id;
+ALTER TABLE requests DROP COLUMN IF EXISTS bin_id;
+
+DROP TABLE IF EXISTS bin_stocks;
+DROP TABLE IF EXISTS locations;
@@ -0,0 +1,36 @@
+-- Location hierarchy inside a warehouse: zone -> aisle -> rack -> bin
+CREATE TABLE locations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    warehouse_id UUID NOT NULL REFERENCES warehouses(id),
+    parent_id UUID REFERENCES locations(id),
+    type VARCHAR(20) NOT NULL CHECK (type IN ('ZONE', 'AISLE', 'RACK', 'BIN')),
+    code VARCHAR(50) NOT NULL,
+    path VARCHAR(255) NOT NULL,
+    name VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT idx_locations_warehouse_path UNIQUE (warehouse_id, path),
+    CHECK ((type = 'ZONE') = (parent_id IS NULL))
+);
+
+-- Bin-level balances; their sum per item/warehouse never exceeds inventory_stocks.quantity
+CREATE TABLE bin_stocks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    item_id UUID NOT NULL REFERENCES inventory(id),
+    warehouse_id UUID NOT NULL REFERENCES warehouses(id),
+    location_id UUID NOT NULL REFERENCES locations(id),
+    quantity INT NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    version INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT idx_bin_stocks_item_location UNIQUE (item_id, location_id)
+);
+
+-- Put-away / pick bins chosen at approval
+ALTER TABLE requests ADD COLUMN bin_id UUID REFERENCES locations(id);
+ALTER TABLE requests ADD COLUMN destination_bin_id UUID REFERENCES locations(id);
+
+-- Indexes
+CREATE INDEX idx_locations_parent_id ON locations(parent_id);
+CREATE INDEX idx_bin_stocks_item_warehouse ON bin_stocks(item_id, warehouse_id);
+CREATE INDEX idx_bin_stocks_location_id ON bin_stocks(location_id);
@@ -0,0 +1,6 @@
+ALTER TABLE requests DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE requests DROP COLUMN IF EXISTS manufactured_at;
+ALTER TABLE requests DROP COLUMN IF EXISTS lot_number;
+
+DROP TABLE IF EXISTS request_lots;
+DROP TABLE IF EXISTS lots;
@@ -0,0 +1,34 @@
+-- Lots / batches per item and warehouse, consumed first-expired-first-out
+CREATE TABLE lots (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    item_id UUID NOT NULL REFERENCES inventory(id),
+    warehouse_id UUID NOT NULL REFERENCES warehouses(id),
+    lot_number VARCHAR(100) NOT NULL,
+    manufactured_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    quantity INT NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    version INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT idx_lots_item_warehouse_number UNIQUE (item_id, warehouse_id, lot_number)
+);
+
+-- Lots consumed by outbound / transfer requests
+CREATE TABLE request_lots (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    request_id UUID NOT NULL REFERENCES requests(id),
+    lot_id UUID NOT NULL REFERENCES lots(id),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Lot received by an inbound request
+ALTER TABLE requests ADD COLUMN lot_number VARCHAR(100);
+ALTER TABLE requests ADD COLUMN manufactured_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE requests ADD COLUMN expires_at TIMESTAMP WITH TIME ZONE;
+
+-- Indexes
+CREATE INDEX idx_lots_fefo ON lots(item_id, warehouse_id, expires_at);
+CREATE INDEX idx_lots_expires_at ON lots(expires_at) WHERE quantity > 0;
+CREATE INDEX idx_request_lots_request_id ON request_lots(request_id);
+CREATE INDEX idx_request_lots_lot_id ON request_lots(lot_id);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS request_serials;
+DROP TABLE IF EXISTS serial_movements;
+DROP TABLE IF EXISTS serial_numbers;
+
+ALTER TABLE inventory DROP COLUMN IF EXISTS serialized;
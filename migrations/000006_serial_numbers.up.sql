@@ -0,0 +1,38 @@
+-- Serialized items are tracked unit by unit
+ALTER TABLE inventory ADD COLUMN serialized BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE serial_numbers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    item_id UUID NOT NULL REFERENCES inventory(id),
+    serial_number VARCHAR(100) NOT NULL,
+    warehouse_id UUID NOT NULL REFERENCES warehouses(id),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('IN_STOCK', 'SHIPPED')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT idx_serial_numbers_item_serial UNIQUE (item_id, serial_number)
+);
+
+-- Movement history of each unit
+CREATE TABLE serial_movements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    serial_id UUID NOT NULL REFERENCES serial_numbers(id),
+    request_id UUID NOT NULL REFERENCES requests(id),
+    type VARCHAR(20) NOT NULL CHECK (type IN ('RECEIVED', 'SHIPPED', 'TRANSFERRED')),
+    from_warehouse_id UUID REFERENCES warehouses(id),
+    to_warehouse_id UUID REFERENCES warehouses(id),
+    user_id UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Serial numbers named on a request
+CREATE TABLE request_serials (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    request_id UUID NOT NULL REFERENCES requests(id),
+    serial_number VARCHAR(100) NOT NULL,
+    CONSTRAINT idx_request_serials_request_serial UNIQUE (request_id, serial_number)
+);
+
+-- Indexes
+CREATE INDEX idx_serial_numbers_serial_number ON serial_numbers(serial_number);
+CREATE INDEX idx_serial_movements_serial_id ON serial_movements(serial_id, created_at);
+CREATE INDEX idx_serial_movements_request_id ON serial_movements(request_id);
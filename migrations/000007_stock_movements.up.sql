@@ -0,0 +1,36 @@
+-- Append-only ledger of every change to an item/warehouse balance
+CREATE TABLE stock_movements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    seq BIGSERIAL NOT NULL UNIQUE,
+    stock_id UUID NOT NULL REFERENCES inventory_stocks(id),
+    item_id UUID NOT NULL REFERENCES inventory(id),
+    warehouse_id UUID NOT NULL REFERENCES warehouses(id),
+    request_id UUID REFERENCES requests(id),
+    type VARCHAR(20) NOT NULL,
+    delta INT NOT NULL,
+    balance_after INT NOT NULL CHECK (balance_after >= 0),
+    user_id UUID REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Existing balances become the opening entries of the ledger
+INSERT INTO stock_movements (stock_id, item_id, warehouse_id, type, delta, balance_after, created_at)
+SELECT id, item_id, warehouse_id, 'OPENING', quantity, quantity, NOW()
+FROM inventory_stocks
+WHERE quantity > 0;
+
+-- Ledger rows can never be changed or removed
+CREATE OR REPLACE FUNCTION stock_movements_immutable() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'stock_movements is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_stock_movements_immutable
+    BEFORE UPDATE OR DELETE ON stock_movements
+    FOR EACH ROW EXECUTE FUNCTION stock_movements_immutable();
+
+-- Indexes
+CREATE INDEX idx_stock_movements_item_created ON stock_movements(item_id, created_at);
+CREATE INDEX idx_stock_movements_stock_seq ON stock_movements(stock_id, seq);
+CREATE INDEX idx_stock_movements_request_id ON stock_movements(request_id);
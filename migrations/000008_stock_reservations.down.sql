@@ -0,0 +1,4 @@
+ALTER TABLE requests DROP COLUMN IF EXISTS reserved_quantity;
+
+ALTER TABLE inventory_stocks DROP CONSTRAINT IF EXISTS inventory_stocks_reserved_check;
+ALTER TABLE inventory_stocks DROP COLUMN IF EXISTS reserved;
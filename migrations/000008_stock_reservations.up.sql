@@ -0,0 +1,32 @@
+-- Quantity held by pending outbound and transfer requests
+ALTER TABLE inventory_stocks ADD COLUMN reserved INT NOT NULL DEFAULT 0;
+ALTER TABLE requests ADD COLUMN reserved_quantity INT NOT NULL DEFAULT 0 CHECK (reserved_quantity >= 0);
+
+-- Reserve for requests already pending, oldest first, as far as stock allows
+WITH ranked AS (
+    SELECT r.id, r.item_id, r.warehouse_id, r.quantity,
+           SUM(r.quantity) OVER (
+               PARTITION BY r.item_id, r.warehouse_id
+               ORDER BY r.created_at, r.id
+           ) AS running
+    FROM requests r
+    WHERE r.status = 'PENDING' AND r.type IN ('OUTBOUND', 'TRANSFER')
+)
+UPDATE requests r
+SET reserved_quantity = ranked.quantity
+FROM ranked
+JOIN inventory_stocks s ON s.item_id = ranked.item_id AND s.warehouse_id = ranked.warehouse_id
+WHERE r.id = ranked.id AND ranked.running <= s.quantity;
+
+UPDATE inventory_stocks s
+SET reserved = held.total
+FROM (
+    SELECT item_id, warehouse_id, SUM(reserved_quantity) AS total
+    FROM requests
+    WHERE reserved_quantity > 0
+    GROUP BY item_id, warehouse_id
+) held
+WHERE s.item_id = held.item_id AND s.warehouse_id = held.warehouse_id;
+
+ALTER TABLE inventory_stocks ADD CONSTRAINT inventory_stocks_reserved_check
+    CHECK (reserved >= 0 AND reserved <= quantity);
@@ -0,0 +1,28 @@
+-- Restore the single-item header from each request's first line; further lines are lost
+ALTER TABLE requests ADD COLUMN bin_id UUID REFERENCES locations(id);
+ALTER TABLE requests ADD COLUMN destination_bin_id UUID REFERENCES locations(id);
+ALTER TABLE requests ADD COLUMN lot_number VARCHAR(100);
+ALTER TABLE requests ADD COLUMN manufactured_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE requests ADD COLUMN expires_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE requests ADD COLUMN reserved_quantity INT NOT NULL DEFAULT 0 CHECK (reserved_quantity >= 0);
+
+UPDATE requests r
+SET item_id = l.item_id,
+    bin_id = l.bin_id,
+    destination_bin_id = l.destination_bin_id,
+    lot_number = l.lot_number,
+    manufactured_at = l.manufactured_at,
+    expires_at = l.expires_at,
+    reserved_quantity = l.reserved_quantity
+FROM request_lines l
+WHERE l.request_id = r.id AND l.line_no = 1;
+
+ALTER TABLE requests ALTER COLUMN item_id SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_request_serials_request_id;
+ALTER TABLE request_serials DROP CONSTRAINT IF EXISTS idx_request_serials_line_serial;
+ALTER TABLE request_serials ADD CONSTRAINT idx_request_serials_request_serial UNIQUE (request_id, serial_number);
+ALTER TABLE request_serials DROP COLUMN IF EXISTS line_id;
+ALTER TABLE request_lots DROP COLUMN IF EXISTS line_id;
+
+DROP TABLE IF EXISTS request_lines;
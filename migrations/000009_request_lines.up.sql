@@ -0,0 +1,53 @@
+-- Requests become a header with one or more item lines
+CREATE TABLE request_lines (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    request_id UUID NOT NULL REFERENCES requests(id),
+    line_no INT NOT NULL CHECK (line_no > 0),
+    item_id UUID NOT NULL REFERENCES inventory(id),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    reserved_quantity INT NOT NULL DEFAULT 0 CHECK (reserved_quantity >= 0),
+    bin_id UUID REFERENCES locations(id),
+    destination_bin_id UUID REFERENCES locations(id),
+    lot_number VARCHAR(100),
+    manufactured_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT idx_request_lines_request_line_no UNIQUE (request_id, line_no)
+);
+
+-- Every existing request becomes a single-line request
+INSERT INTO request_lines (request_id, line_no, item_id, quantity, reserved_quantity,
+                           bin_id, destination_bin_id, lot_number, manufactured_at, expires_at,
+                           created_at, updated_at)
+SELECT id, 1, item_id, quantity, reserved_quantity,
+       bin_id, destination_bin_id, lot_number, manufactured_at, expires_at,
+       created_at, updated_at
+FROM requests;
+
+-- Lot allocations and serial numbers belong to a line
+ALTER TABLE request_lots ADD COLUMN line_id UUID REFERENCES request_lines(id);
+UPDATE request_lots rl SET line_id = l.id
+FROM request_lines l WHERE l.request_id = rl.request_id;
+ALTER TABLE request_lots ALTER COLUMN line_id SET NOT NULL;
+
+ALTER TABLE request_serials ADD COLUMN line_id UUID REFERENCES request_lines(id);
+UPDATE request_serials rs SET line_id = l.id
+FROM request_lines l WHERE l.request_id = rs.request_id;
+ALTER TABLE request_serials ALTER COLUMN line_id SET NOT NULL;
+ALTER TABLE request_serials DROP CONSTRAINT IF EXISTS idx_request_serials_request_serial;
+ALTER TABLE request_serials ADD CONSTRAINT idx_request_serials_line_serial UNIQUE (line_id, serial_number);
+
+-- The header keeps item_id only for single-line requests
+ALTER TABLE requests ALTER COLUMN item_id DROP NOT NULL;
+ALTER TABLE requests DROP COLUMN IF EXISTS bin_id;
+ALTER TABLE requests DROP COLUMN IF EXISTS destination_bin_id;
+ALTER TABLE requests DROP COLUMN IF EXISTS lot_number;
+ALTER TABLE requests DROP COLUMN IF EXISTS manufactured_at;
+ALTER TABLE requests DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE requests DROP COLUMN IF EXISTS reserved_quantity;
+
+-- Indexes
+CREATE INDEX idx_request_lines_item_id ON request_lines(item_id);
+CREATE INDEX idx_request_lots_line_id ON request_lots(line_id);
+CREATE INDEX idx_request_serials_request_id ON request_serials(request_id);
@@ -0,0 +1,13 @@
+ALTER TABLE request_serials DROP COLUMN IF EXISTS shipment_id;
+ALTER TABLE request_lots DROP COLUMN IF EXISTS shipment_id;
+
+DROP TABLE IF EXISTS shipment_lines;
+DROP TABLE IF EXISTS shipments;
+
+ALTER TABLE request_lines DROP CONSTRAINT IF EXISTS request_lines_shipped_quantity_check;
+ALTER TABLE request_lines DROP COLUMN IF EXISTS shipped_quantity;
+
+UPDATE requests SET status = 'APPROVED' WHERE status = 'PARTIALLY_FULFILLED';
+ALTER TABLE requests DROP CONSTRAINT requests_status_check;
+ALTER TABLE requests ADD CONSTRAINT requests_status_check
+    CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED', 'COMPLETED'));
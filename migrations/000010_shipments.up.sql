@@ -0,0 +1,39 @@
+-- Approved outbound requests are fulfilled by one or more shipments
+ALTER TABLE requests DROP CONSTRAINT requests_status_check;
+ALTER TABLE requests ADD CONSTRAINT requests_status_check
+    CHECK (status IN ('PENDING', 'APPROVED', 'PARTIALLY_FULFILLED', 'REJECTED', 'COMPLETED'));
+
+ALTER TABLE request_lines ADD COLUMN shipped_quantity INT NOT NULL DEFAULT 0;
+ALTER TABLE request_lines ADD CONSTRAINT request_lines_shipped_quantity_check
+    CHECK (shipped_quantity >= 0 AND shipped_quantity <= quantity);
+
+-- Outbound requests completed before shipments existed were shipped in full on approval
+UPDATE request_lines l SET shipped_quantity = l.quantity
+FROM requests r
+WHERE r.id = l.request_id AND r.type = 'OUTBOUND' AND r.status = 'COMPLETED';
+
+CREATE TABLE shipments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    request_id UUID NOT NULL REFERENCES requests(id),
+    shipped_by UUID NOT NULL REFERENCES users(id),
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE shipment_lines (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    shipment_id UUID NOT NULL REFERENCES shipments(id),
+    line_id UUID NOT NULL REFERENCES request_lines(id),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    bin_id UUID REFERENCES locations(id),
+    CONSTRAINT idx_shipment_lines_shipment_line UNIQUE (shipment_id, line_id)
+);
+
+-- Lots and serial numbers picked by a shipment
+ALTER TABLE request_lots ADD COLUMN shipment_id UUID REFERENCES shipments(id);
+ALTER TABLE request_serials ADD COLUMN shipment_id UUID REFERENCES shipments(id);
+
+-- Indexes
+CREATE INDEX idx_shipments_request_id ON shipments(request_id, created_at);
+CREATE INDEX idx_shipment_lines_line_id ON shipment_lines(line_id);
+CREATE INDEX idx_request_lots_shipment_id ON request_lots(shipment_id);
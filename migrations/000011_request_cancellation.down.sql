@@ -0,0 +1,4 @@
+UPDATE requests SET status = 'REJECTED' WHERE status = 'CANCELLED';
+ALTER TABLE requests DROP CONSTRAINT requests_status_check;
+ALTER TABLE requests ADD CONSTRAINT requests_status_check
+    CHECK (status IN ('PENDING', 'APPROVED', 'PARTIALLY_FULFILLED', 'REJECTED', 'COMPLETED'));
@@ -0,0 +1,4 @@
+-- Requests may be cancelled by their creator or an admin before they complete
+ALTER TABLE requests DROP CONSTRAINT requests_status_check;
+ALTER TABLE requests ADD CONSTRAINT requests_status_check
+    CHECK (status IN ('PENDING', 'APPROVED', 'PARTIALLY_FULFILLED', 'REJECTED', 'COMPLETED', 'CANCELLED'));
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS request_approvals;
+DROP TABLE IF EXISTS approval_policy_steps;
+DROP TABLE IF EXISTS approval_policies;
+
+ALTER TABLE inventory DROP COLUMN IF EXISTS unit_value;
+ALTER TABLE inventory DROP COLUMN IF EXISTS category;
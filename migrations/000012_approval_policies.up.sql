@@ -0,0 +1,51 @@
+-- Item attributes matched by approval policies
+ALTER TABLE inventory ADD COLUMN category VARCHAR(100);
+ALTER TABLE inventory ADD COLUMN unit_value NUMERIC(14,2) NOT NULL DEFAULT 0 CHECK (unit_value >= 0);
+
+-- Approval chains required for matching requests
+CREATE TABLE approval_policies (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    request_type VARCHAR(20) CHECK (request_type IN ('INBOUND', 'OUTBOUND', 'TRANSFER')),
+    category VARCHAR(100),
+    quantity_over INT NOT NULL DEFAULT 0 CHECK (quantity_over >= 0),
+    value_over NUMERIC(14,2) NOT NULL DEFAULT 0 CHECK (value_over >= 0),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE approval_policy_steps (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    policy_id UUID NOT NULL REFERENCES approval_policies(id),
+    step_no INT NOT NULL CHECK (step_no > 0),
+    role VARCHAR(50) NOT NULL CHECK (role IN ('supervisor', 'admin')),
+    CONSTRAINT idx_approval_policy_steps_policy_step UNIQUE (policy_id, step_no)
+);
+
+-- Approval chain of each request, signed step by step
+CREATE TABLE request_approvals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    request_id UUID NOT NULL REFERENCES requests(id),
+    step_no INT NOT NULL CHECK (step_no > 0),
+    role VARCHAR(50) NOT NULL CHECK (role IN ('supervisor', 'admin')),
+    policy_id UUID REFERENCES approval_policies(id),
+    approved_by UUID REFERENCES users(id),
+    approved_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT idx_request_approvals_request_step UNIQUE (request_id, step_no),
+    CHECK ((approved_by IS NULL) = (approved_at IS NULL))
+);
+
+-- Existing requests needed a single supervisor approval
+INSERT INTO request_approvals (request_id, step_no, role, approved_by, approved_at)
+SELECT id, 1, 'supervisor',
+       CASE WHEN status <> 'PENDING' THEN approved_by END,
+       CASE WHEN status <> 'PENDING' THEN updated_at END
+FROM requests
+WHERE status = 'PENDING'
+   OR (status IN ('APPROVED', 'PARTIALLY_FULFILLED', 'COMPLETED') AND approved_by IS NOT NULL);
+
+-- Indexes
+CREATE INDEX idx_approval_policies_active ON approval_policies(active);
+CREATE INDEX idx_request_approvals_approved_by ON request_approvals(approved_by);
@@ -0,0 +1,3 @@
+ALTER TABLE request_approvals DROP COLUMN IF EXISTS on_behalf_of;
+
+DROP TABLE IF EXISTS approval_delegations;
@@ -0,0 +1,22 @@
+-- Supervisors and admins hand their approval authority to a delegate for a date range
+CREATE TABLE approval_delegations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    delegator_id UUID NOT NULL REFERENCES users(id),
+    delegate_id UUID NOT NULL REFERENCES users(id),
+    request_types JSONB NOT NULL DEFAULT '[]',
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    reason TEXT,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (delegator_id <> delegate_id),
+    CHECK (ends_at > starts_at)
+);
+
+-- Original approver of a step signed by a delegate
+ALTER TABLE request_approvals ADD COLUMN on_behalf_of UUID REFERENCES users(id);
+
+-- Indexes
+CREATE INDEX idx_approval_delegations_delegate ON approval_delegations(delegate_id, starts_at, ends_at)
+    WHERE revoked_at IS NULL;
+CREATE INDEX idx_approval_delegations_delegator_id ON approval_delegations(delegator_id);
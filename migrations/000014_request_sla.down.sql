@@ -0,0 +1,9 @@
+-- The system user is kept: audit history written by background jobs references it
+
+DROP INDEX IF EXISTS idx_requests_pending_created;
+ALTER TABLE requests DROP COLUMN IF EXISTS escalated_at;
+
+UPDATE requests SET status = 'REJECTED' WHERE status = 'EXPIRED';
+ALTER TABLE requests DROP CONSTRAINT requests_status_check;
+ALTER TABLE requests ADD CONSTRAINT requests_status_check
+    CHECK (status IN ('PENDING', 'APPROVED', 'PARTIALLY_FULFILLED', 'REJECTED', 'COMPLETED', 'CANCELLED'));
@@ -0,0 +1,13 @@
+-- Requests pending past their SLA are escalated once and eventually expire
+ALTER TABLE requests DROP CONSTRAINT requests_status_check;
+ALTER TABLE requests ADD CONSTRAINT requests_status_check
+    CHECK (status IN ('PENDING', 'APPROVED', 'PARTIALLY_FULFILLED', 'REJECTED', 'COMPLETED', 'CANCELLED', 'EXPIRED'));
+
+ALTER TABLE requests ADD COLUMN escalated_at TIMESTAMPTZ;
+
+CREATE INDEX idx_requests_pending_created ON requests(type, created_at) WHERE status = 'PENDING';
+
+-- Background jobs audit as the system user; its password hash matches no password
+INSERT INTO users (id, name, email, password_hash, role)
+VALUES ('00000000-0000-0000-0000-000000000001', 'System', 'system@warehousex.local', '!', 'staff')
+ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS request_comments;
+ALTER TABLE requests DROP COLUMN IF EXISTS rejection_reason;
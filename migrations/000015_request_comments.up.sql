@@ -0,0 +1,15 @@
+-- Rejections carry the approver's reason
+ALTER TABLE requests ADD COLUMN rejection_reason TEXT;
+
+-- Comment thread of a request
+CREATE TABLE request_comments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    request_id UUID NOT NULL REFERENCES requests(id),
+    user_id UUID NOT NULL REFERENCES users(id),
+    role VARCHAR(50) NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Indexes
+CREATE INDEX idx_request_comments_request_id ON request_comments(request_id, created_at);
@@ -0,0 +1,15 @@
+-- Files uploaded to a request; the content lives in the blob store
+CREATE TABLE request_attachments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    request_id UUID NOT NULL REFERENCES requests(id),
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL CHECK (size > 0),
+    checksum VARCHAR(64) NOT NULL,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    uploaded_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Indexes
+CREATE INDEX idx_request_attachments_request_id ON request_attachments(request_id, created_at);
@@ -0,0 +1 @@
+ALTER TABLE inventory_stocks DROP COLUMN IF EXISTS fence_token;
@@ -0,0 +1,2 @@
+-- Fencing token of the last lock holder that wrote each balance
+ALTER TABLE inventory_stocks ADD COLUMN fence_token BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS user_invitations;
+
+-- Without the status column pending accounts would be able to log in; give them a
+-- password hash that matches no password first
+UPDATE users SET password_hash = '!' WHERE status <> 'active';
+
+ALTER TABLE users DROP COLUMN IF EXISTS status;
@@ -0,0 +1,21 @@
+-- Self-registered accounts wait for an admin to activate them
+ALTER TABLE users ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active'
+    CONSTRAINT users_status_check CHECK (status IN ('active', 'pending'));
+
+-- Single-use invitations issued by admins; only the token hash is stored
+CREATE TABLE user_invitations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(50) NOT NULL CHECK (role IN ('staff', 'supervisor', 'admin', 'auditor')),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_by UUID NOT NULL REFERENCES users(id),
+    accepted_at TIMESTAMP WITH TIME ZONE,
+    accepted_by UUID REFERENCES users(id),
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Indexes
+CREATE INDEX idx_user_invitations_email ON user_invitations(email);
+CREATE INDEX idx_users_status ON users(status) WHERE status <> 'active';
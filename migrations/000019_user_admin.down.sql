@@ -0,0 +1,9 @@
+ALTER TABLE users DROP COLUMN IF EXISTS reset_token_expires_at;
+ALTER TABLE users DROP COLUMN IF EXISTS reset_token_hash;
+ALTER TABLE users DROP COLUMN IF EXISTS password_reset_required;
+
+-- Deactivated users must stay unable to log in; pending is the closest status
+UPDATE users SET status = 'pending' WHERE status = 'deactivated';
+ALTER TABLE users DROP CONSTRAINT users_status_check;
+ALTER TABLE users ADD CONSTRAINT users_status_check
+    CHECK (status IN ('active', 'pending'));
@@ -0,0 +1,2 @@
+-- Accounts locked after repeated failed logins, until the time passes or an admin unlocks them
+ALTER TABLE users ADD COLUMN locked_until TIMESTAMP WITH TIME ZONE;